@@ -10,8 +10,28 @@ import (
 	"github.com/yankghjh/selfhosted_store/cli/project"
 
 	// modules
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/api"
 	_ "github.com/yankghjh/selfhosted_store/cli/modules/app"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/caprover"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/casaos"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/coolify"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/dashboard"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/docker-run"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/feed"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/hassio"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/helm"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/images"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/kubernetes"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/markdown"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/nomad"
 	_ "github.com/yankghjh/selfhosted_store/cli/modules/portainer"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/proxy"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/quadlet"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/search"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/site"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/swarm"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/synology"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/umbrel"
 	_ "github.com/yankghjh/selfhosted_store/cli/modules/unraid"
 	_ "github.com/yankghjh/selfhosted_store/cli/modules/yacht"
 )
@@ -5,6 +5,11 @@ import (
 
 	// modules
 	_ "github.com/yankghjh/selfhosted_store/cli/modules/docker-compose"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/docker-run"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/kubernetes"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/proxy"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/quadlet"
+	_ "github.com/yankghjh/selfhosted_store/cli/modules/swarm"
 
 	"github.com/yankghjh/selfhosted_store/cli/project"
 )
@@ -0,0 +1,332 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("api", Generater)
+}
+
+// Version of the api, the path prefix of all files
+const Version = "v1"
+
+// DataPath is the placeholder of app data root in volume source of services,
+// clients replace it with the data directory of the app
+const DataPath = "{data}"
+
+var defaultPageSize = 100
+
+// Index is the entry of api, apps summaries are split to shards
+type Index struct {
+	Version    string   `json:"version"`
+	Generated  string   `json:"generated"`
+	Total      int      `json:"total"`
+	PageSize   int      `json:"page_size"`
+	Shards     []string `json:"shards"`
+	Categories string   `json:"categories"`
+	Apps       string   `json:"apps"`
+}
+
+// Shard of apps summaries
+type Shard struct {
+	Page  int        `json:"page"`
+	Pages int        `json:"pages"`
+	Apps  []*Summary `json:"apps"`
+}
+
+// Summary of app in shards
+type Summary struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Icon        string   `json:"icon,omitempty"`
+	Categories  []string `json:"categories"`
+	URL         string   `json:"url"`
+	Downloads   int64    `json:"downloads,omitempty"`
+	Stars       int64    `json:"stars,omitempty"`
+	LastUpdate  string   `json:"last_update,omitempty"`
+}
+
+// Category with the ids of its apps
+type Category struct {
+	Name  string   `json:"name"`
+	Slug  string   `json:"slug"`
+	Count int      `json:"count"`
+	Apps  []string `json:"apps"`
+}
+
+// App is the full canonical application
+type App struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Overview    string       `json:"overview,omitempty"`
+	Categories  []string     `json:"categories"`
+	Icon        string       `json:"icon,omitempty"`
+	WebUI       *WebUI       `json:"webui,omitempty"`
+	Links       []*Link      `json:"links"`
+	Parameters  []*Parameter `json:"parameters"`
+	Services    []*Service   `json:"services"`
+	Stats       *Stats       `json:"stats"`
+	Source      *Source      `json:"source,omitempty"`
+	Variants    []*App       `json:"variants,omitempty"`
+}
+
+// Service in docker compose format, with its name
+type Service struct {
+	Name string `json:"name"`
+	*types.ServiceConfig
+}
+
+// MarshalJSON of service, empty objects of the compose types are dropped
+func (s *Service) MarshalJSON() ([]byte, error) {
+	res, err := json.Marshal(s.ServiceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]interface{}{}
+	d := json.NewDecoder(bytes.NewReader(res))
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return nil, err
+	}
+	prune(m)
+	m["name"] = s.Name
+
+	return json.Marshal(m)
+}
+
+// prune empty objects of m recursively
+func prune(m map[string]interface{}) {
+	for k, v := range m {
+		child, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		prune(child)
+		if len(child) == 0 {
+			delete(m, k)
+		}
+	}
+}
+
+// WebUI of app
+type WebUI struct {
+	Scheme string `json:"scheme"`
+	Port   uint32 `json:"port"`
+	Path   string `json:"path"`
+}
+
+// Link of app
+type Link struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Parameter of app
+type Parameter struct {
+	Type        string `json:"type"`
+	Target      string `json:"target"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Display     string `json:"display,omitempty"`
+	Required    bool   `json:"required"`
+	Mask        bool   `json:"mask"`
+}
+
+// Stats of popularity and freshness
+type Stats struct {
+	Downloads  int64  `json:"downloads"`
+	Stars      int64  `json:"stars"`
+	FirstSeen  string `json:"first_seen,omitempty"`
+	LastUpdate string `json:"last_update,omitempty"`
+	Changes    string `json:"changes,omitempty"`
+}
+
+// Source is the provenance of app
+type Source struct {
+	Loader   string `json:"loader"`
+	Type     string `json:"type"`
+	Location string `json:"location,omitempty"`
+}
+
+// Generater versioned static json api
+//
+//	page_size: 100
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	pageSize := o.Config.GetInt("page_size")
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	files := map[string]interface{}{}
+
+	summaries := []*Summary{}
+	categories := []*Category{}
+	slugs := map[string]*Category{}
+	for _, a := range apps {
+		app := NewApp(a)
+		files["apps/"+app.ID+".json"] = app
+		summaries = append(summaries, NewSummary(app))
+
+		for _, name := range app.Categories {
+			c, ok := slugs[name]
+			if !ok {
+				c = &Category{Name: name, Slug: project.Slugify(name), Apps: []string{}}
+				slugs[name] = c
+				categories = append(categories, c)
+			}
+			c.Count++
+			c.Apps = append(c.Apps, app.ID)
+		}
+	}
+	files["categories.json"] = categories
+
+	index := &Index{
+		Version:    Version,
+		Generated:  time.Now().UTC().Format(time.RFC3339),
+		Total:      len(summaries),
+		PageSize:   pageSize,
+		Shards:     []string{},
+		Categories: "categories.json",
+		Apps:       "apps/{id}.json",
+	}
+	pages := (len(summaries) + pageSize - 1) / pageSize
+	for page := 1; page <= pages; page++ {
+		end := page * pageSize
+		if end > len(summaries) {
+			end = len(summaries)
+		}
+		name := "index/" + strconv.Itoa(page) + ".json"
+		index.Shards = append(index.Shards, name)
+		files[name] = &Shard{Page: page, Pages: pages, Apps: summaries[(page-1)*pageSize : end]}
+	}
+	files["index.json"] = index
+
+	for name, schema := range Schemas {
+		files["schema/"+name] = json.RawMessage(schema)
+	}
+
+	for name, v := range files {
+		res, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal api %s error: %s", name, err.Error())
+		}
+
+		filename := o.Project.GetDistPath("api", Version, name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		err = ioutil.WriteFile(filename, res, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write api file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// NewApp of api from application
+func NewApp(a *project.Application) *App {
+	app := &App{
+		ID:          a.GetID(),
+		Name:        a.Name,
+		Description: a.Description,
+		Overview:    a.Overview,
+		Categories:  []string{},
+		Icon:        a.Icon,
+		Links:       []*Link{},
+		Parameters:  []*Parameter{},
+		Services:    []*Service{},
+		Stats: &Stats{
+			Downloads:  a.Downloads,
+			Stars:      a.Stars,
+			FirstSeen:  formatTime(a.FirstSeen),
+			LastUpdate: formatTime(a.LastUpdate),
+			Changes:    a.Changes,
+		},
+	}
+	for _, s := range a.Services {
+		app.Services = append(app.Services, NewService(s))
+	}
+
+	for _, c := range a.Category {
+		if c != "" {
+			app.Categories = append(app.Categories, c)
+		}
+	}
+	if a.WebUI != nil {
+		app.WebUI = &WebUI{Scheme: a.WebUI.Scheme, Port: a.WebUI.Port, Path: a.WebUI.Path}
+	}
+	for _, l := range a.Links {
+		app.Links = append(app.Links, &Link{Type: l.Type, URL: l.URL})
+	}
+	for _, p := range a.Parameters {
+		parameter := Parameter(*p)
+		app.Parameters = append(app.Parameters, &parameter)
+	}
+	if a.Source != nil {
+		app.Source = &Source{Loader: a.Source.Loader, Type: a.Source.Type, Location: a.Source.Location}
+	}
+	for _, v := range a.Variants {
+		app.Variants = append(app.Variants, NewApp(v))
+	}
+
+	return app
+}
+
+// NewService of api from compose service, the data path prefix of volumes
+// is resolved to DataPath as bind mount
+func NewService(s *types.ServiceConfig) *Service {
+	service := *s
+	service.Volumes = []types.ServiceVolumeConfig{}
+	for _, v := range s.Volumes {
+		if v.Source == project.DataPathPrefix || strings.HasPrefix(v.Source, project.DataPathPrefix+"/") {
+			v.Source = project.ResolveDataPath(v.Source, DataPath)
+			v.Type = "bind"
+		}
+		service.Volumes = append(service.Volumes, v)
+	}
+
+	return &Service{Name: s.Name, ServiceConfig: &service}
+}
+
+// NewSummary of app
+func NewSummary(app *App) *Summary {
+	return &Summary{
+		ID:          app.ID,
+		Name:        app.Name,
+		Description: app.Description,
+		Icon:        app.Icon,
+		Categories:  app.Categories,
+		URL:         "apps/" + app.ID + ".json",
+		Downloads:   app.Stats.Downloads,
+		Stars:       app.Stats.Stars,
+		LastUpdate:  app.Stats.LastUpdate,
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/spf13/viper"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestServiceMarshalJSON(t *testing.T) {
+	tz := "UTC"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test:1.0",
+		Environment: types.MappingWithEquals{"TZ": &tz},
+		Ports:       []types.ServicePortConfig{{Target: 8080, Published: 8081}},
+	})
+	a.Links = append(a.Links, &project.Link{Type: "wiki", URL: "https://example.com/wiki"})
+
+	res, err := json.Marshal(NewApp(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{`"build"`, `"credential_spec"`, `"deploy"`} {
+		if strings.Contains(string(res), field) {
+			t.Errorf("empty %s is serialised: %s", field, res)
+		}
+	}
+	for _, field := range []string{`"name":"test"`, `"image":"foo/test:1.0"`, `"TZ":"UTC"`, `"published":8081`, `"type":"wiki"`} {
+		if !strings.Contains(string(res), field) {
+			t.Errorf("%s is missing: %s", field, res)
+		}
+	}
+}
+
+func TestSchemasAreValidJSON(t *testing.T) {
+	for name, schema := range Schemas {
+		if !json.Valid([]byte(schema)) {
+			t.Errorf("schema %s is not valid json", name)
+		}
+	}
+}
+
+func TestNewServiceResolvesDataPath(t *testing.T) {
+	s := NewService(&types.ServiceConfig{
+		Name: "test",
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: "volume", Source: "!data/config", Target: "/config"},
+			{Type: "volume", Source: "!database", Target: "/db"},
+			{Type: "bind", Source: "/etc/localtime", Target: "/etc/localtime"},
+		},
+	})
+
+	expected := []types.ServiceVolumeConfig{
+		{Type: "bind", Source: "{data}/config", Target: "/config"},
+		{Type: "volume", Source: "!database", Target: "/db"},
+		{Type: "bind", Source: "/etc/localtime", Target: "/etc/localtime"},
+	}
+	for i, v := range s.Volumes {
+		if v != expected[i] {
+			t.Errorf("volume %d expected %+v, got %+v", i, expected[i], v)
+		}
+	}
+}
+
+func TestGeneratedFilesMatchSchemas(t *testing.T) {
+	dist, err := ioutil.TempDir("", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dist)
+
+	tz := "UTC"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Category = []string{"Media"}
+	a.WebUI = &project.WebUI{Scheme: "http", Port: 8080, Path: "/"}
+	a.Parameters = append(a.Parameters, &project.Parameter{Type: project.ParameterTypePort, Target: "8080", Name: "Web UI", Default: "8080"})
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test:1.0",
+		Environment: types.MappingWithEquals{"TZ": &tz},
+		Ports:       []types.ServicePortConfig{{Target: 8080, Published: 8080}},
+		Volumes:     []types.ServiceVolumeConfig{{Type: "volume", Source: "!data/config", Target: "/config"}},
+	})
+	a.Variants = append(a.Variants, project.NewApplication())
+	a.Variants[0].Name = "Test Lite"
+
+	p := project.NewProject(viper.New())
+	p.Dist = dist
+	p.Apps = append(p.Apps, a)
+	if err := Generater(&project.Operator{Name: "api", Config: viper.New(), Project: p}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, schema := range map[string]string{
+		"index.json":                  indexSchema,
+		"index/1.json":                shardSchema,
+		"categories.json":             categoriesSchema,
+		"apps/" + a.GetID() + ".json": appSchema,
+	} {
+		res, err := ioutil.ReadFile(filepath.Join(dist, "api", Version, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s, v interface{}
+		if err := json.Unmarshal([]byte(schema), &s); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(res, &v); err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range validate(s.(map[string]interface{}), s.(map[string]interface{}), v, "") {
+			t.Errorf("%s: %s", name, e)
+		}
+	}
+	if res, _ := ioutil.ReadFile(filepath.Join(dist, "api", Version, "apps", a.GetID()+".json")); strings.Contains(string(res), project.DataPathPrefix) {
+		t.Errorf("data path prefix is published: %s", res)
+	}
+}
+
+// validate v against the subset of json schema used by Schemas: type,
+// required, properties, items, enum, const, minimum and the root $ref
+func validate(root, schema map[string]interface{}, v interface{}, path string) []string {
+	if ref, ok := schema["$ref"]; ok && ref == "#" {
+		schema = root
+	}
+
+	errs := []string{}
+	switch schema["type"] {
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return append(errs, path+" is not an object")
+		}
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := m[name.(string)]; !ok {
+					errs = append(errs, path+"."+name.(string)+" is required")
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			for name, child := range properties {
+				if value, ok := m[name]; ok {
+					errs = append(errs, validate(root, child.(map[string]interface{}), value, path+"."+name)...)
+				}
+			}
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return append(errs, path+" is not an array")
+		}
+		if child, ok := schema["items"].(map[string]interface{}); ok {
+			for _, item := range items {
+				errs = append(errs, validate(root, child, item, path+"[]")...)
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return append(errs, path+" is not a string")
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			return append(errs, path+" is not an integer")
+		}
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			errs = append(errs, path+" is less than minimum")
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return append(errs, path+" is not a boolean")
+		}
+	}
+
+	if c, ok := schema["const"]; ok && c != v {
+		errs = append(errs, path+" is not the const value")
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			found = found || e == v
+		}
+		if !found {
+			errs = append(errs, path+" is not in enum")
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,175 @@
+package api
+
+// Schemas of api files, file name to json schema
+var Schemas = map[string]string{
+	"index.schema.json":      indexSchema,
+	"shard.schema.json":      shardSchema,
+	"categories.schema.json": categoriesSchema,
+	"app.schema.json":        appSchema,
+}
+
+var indexSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Index",
+  "description": "Entry of the api, paths are relative to the api version root",
+  "type": "object",
+  "required": ["version", "generated", "total", "page_size", "shards", "categories", "apps"],
+  "properties": {
+    "version": {"type": "string", "const": "v1"},
+    "generated": {"type": "string", "format": "date-time"},
+    "total": {"type": "integer", "minimum": 0},
+    "page_size": {"type": "integer", "minimum": 1},
+    "shards": {
+      "description": "Paths of shards in page order",
+      "type": "array",
+      "items": {"type": "string"}
+    },
+    "categories": {"type": "string", "description": "Path of categories"},
+    "apps": {"type": "string", "description": "Path template of apps, {id} is the app id"}
+  }
+}`
+
+var shardSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Shard",
+  "description": "A page of app summaries",
+  "type": "object",
+  "required": ["page", "pages", "apps"],
+  "properties": {
+    "page": {"type": "integer", "minimum": 1},
+    "pages": {"type": "integer", "minimum": 1},
+    "apps": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["id", "name", "description", "categories", "url"],
+        "properties": {
+          "id": {"type": "string"},
+          "name": {"type": "string"},
+          "description": {"type": "string"},
+          "icon": {"type": "string"},
+          "categories": {"type": "array", "items": {"type": "string"}},
+          "url": {"type": "string", "description": "Path of the full app"},
+          "downloads": {"type": "integer"},
+          "stars": {"type": "integer"},
+          "last_update": {"type": "string", "format": "date-time"}
+        }
+      }
+    }
+  }
+}`
+
+var categoriesSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Categories",
+  "type": "array",
+  "items": {
+    "type": "object",
+    "required": ["name", "slug", "count", "apps"],
+    "properties": {
+      "name": {"type": "string"},
+      "slug": {"type": "string"},
+      "count": {"type": "integer", "minimum": 0},
+      "apps": {"type": "array", "items": {"type": "string"}, "description": "Ids of apps"}
+    }
+  }
+}`
+
+var appSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "App",
+  "description": "Full canonical application",
+  "type": "object",
+  "required": ["id", "name", "description", "categories", "links", "parameters", "services", "stats"],
+  "properties": {
+    "id": {"type": "string"},
+    "name": {"type": "string"},
+    "description": {"type": "string"},
+    "overview": {"type": "string"},
+    "categories": {"type": "array", "items": {"type": "string"}},
+    "icon": {"type": "string", "description": "Icon url"},
+    "webui": {
+      "type": "object",
+      "required": ["scheme", "port", "path"],
+      "properties": {
+        "scheme": {"type": "string"},
+        "port": {"type": "integer", "description": "Container port of web ui"},
+        "path": {"type": "string"}
+      }
+    },
+    "links": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["type", "url"],
+        "properties": {
+          "type": {"type": "string", "description": "support, project, donate, registry or any other kind of link"},
+          "url": {"type": "string"}
+        }
+      }
+    },
+    "parameters": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["type", "target", "required", "mask"],
+        "properties": {
+          "type": {"type": "string", "enum": ["Port", "Path", "Variable", "Device", "Label", "Secret"]},
+          "target": {"type": "string", "description": "Container port, container path or environment name"},
+          "name": {"type": "string"},
+          "description": {"type": "string"},
+          "default": {"type": "string"},
+          "display": {"type": "string", "enum": ["always", "advanced", "hidden", ""]},
+          "required": {"type": "boolean"},
+          "mask": {"type": "boolean"}
+        }
+      }
+    },
+    "services": {
+      "description": "Services in docker compose format, {data} in volume sources is the data directory of the app",
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["name", "image"],
+        "properties": {
+          "name": {"type": "string"},
+          "image": {"type": "string"},
+          "volumes": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "required": ["target"],
+              "properties": {
+                "type": {"type": "string"},
+                "source": {"type": "string", "description": "Host path, named volume, or bind mount under the app data directory when prefixed with {data}"},
+                "target": {"type": "string"}
+              }
+            }
+          }
+        }
+      }
+    },
+    "stats": {
+      "type": "object",
+      "required": ["downloads", "stars"],
+      "properties": {
+        "downloads": {"type": "integer"},
+        "stars": {"type": "integer"},
+        "first_seen": {"type": "string", "format": "date-time"},
+        "last_update": {"type": "string", "format": "date-time"},
+        "changes": {"type": "string"}
+      }
+    },
+    "source": {
+      "description": "Provenance of the app",
+      "type": "object",
+      "required": ["loader", "type"],
+      "properties": {
+        "loader": {"type": "string"},
+        "type": {"type": "string"},
+        "location": {"type": "string"}
+      }
+    },
+    "variants": {"type": "array", "items": {"$ref": "#"}}
+  }
+}`
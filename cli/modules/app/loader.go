@@ -33,7 +33,9 @@ func Loader(o *project.Operator) error {
 
 	for _, ctx := range ctxs {
 		a := project.NewApplication()
+		a.ID = ctx.Name
 		a.Name = ctx.Name
+		a.Source = &project.Source{Location: path + "/" + ctx.Name}
 
 		plugins := []LoaderPlugin{LoadDockerCompose, LoadApp, LoadIcon}
 		for _, f := range plugins {
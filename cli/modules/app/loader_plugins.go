@@ -29,6 +29,37 @@ func LoadApp(ctx *Context, a *project.Application) error {
 	a.Name = data.GetString("name")
 	a.Description = data.GetString("description")
 	a.Overview = data.GetString("overview")
+	a.Category = data.GetStringSlice("categories")
+
+	if data.IsSet("webui") {
+		a.WebUI = &project.WebUI{
+			Scheme: data.GetString("webui.scheme"),
+			Port:   data.GetUint32("webui.port"),
+			Path:   data.GetString("webui.path"),
+		}
+	}
+
+	links := []*project.Link{}
+	err = data.UnmarshalKey("links", &links)
+	if err != nil {
+		return fmt.Errorf("read links of %s error: %s", path, err.Error())
+	}
+	a.Links = append(a.Links, links...)
+
+	parameters := []*project.Parameter{}
+	err = data.UnmarshalKey("parameters", &parameters)
+	if err != nil {
+		return fmt.Errorf("read parameters of %s error: %s", path, err.Error())
+	}
+	a.Parameters = append(a.Parameters, parameters...)
+
+	if data.IsSet("deploy") {
+		a.Deploy = &project.Deploy{}
+		err = data.UnmarshalKey("deploy", a.Deploy)
+		if err != nil {
+			return fmt.Errorf("read deploy of %s error: %s", path, err.Error())
+		}
+	}
 
 	return nil
 }
@@ -41,7 +72,7 @@ func LoadDockerCompose(ctx *Context, a *project.Application) error {
 		return fmt.Errorf("read file %s error: %s", path, err.Error())
 	}
 
-	compose.LoadApplication(a, payload)
+	err = compose.LoadApplication(a, payload)
 	if err != nil {
 		return fmt.Errorf("load application form %s error: %s", path, err.Error())
 	}
@@ -75,6 +106,7 @@ func LoadIcon(ctx *Context, a *project.Application) error {
 	}
 
 	a.Icon = ctx.Config.GetString("icon.basepath") + ctx.Name + ".png"
+	a.IconFile = path
 
 	return nil
 }
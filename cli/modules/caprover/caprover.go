@@ -0,0 +1,198 @@
+package caprover
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/modules/paas"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("caprover", Generater)
+}
+
+// variable of the app name in caprover
+const appName = "$$cap_appname"
+
+// Generater caprover one-click apps in the layout of an apps repository,
+// public/v4/apps/<id>.yml and public/v4/logos/<id>.png
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{}
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		t := paas.NewTemplate(a)
+		res, err := yaml.Marshal(CapRover(t))
+		if err != nil {
+			return fmt.Errorf("marshal caprover app %s error: %s", a.Name, err.Error())
+		}
+		files["public/v4/apps/"+t.ID+".yml"] = res
+
+		if a.IconFile != "" {
+			icon, err := ioutil.ReadFile(a.IconFile)
+			if err != nil {
+				return fmt.Errorf("read icon %s error: %s", a.IconFile, err.Error())
+			}
+			files["public/v4/logos/"+t.ID+".png"] = icon
+		}
+	}
+
+	return paas.WriteFiles(o, "caprover", files)
+}
+
+// CapRover one-click app v4 of template, parameters are mapped to $$cap_ variables
+func CapRover(t *paas.Template) yaml.MapSlice {
+	ids := capVariables(t.Variables)
+	services := yaml.MapSlice{}
+	for _, s := range t.Services {
+		service := yaml.MapSlice{}
+
+		image := s.Image
+		if s.Name == t.Main && t.Version != "" {
+			image += ":$$cap_version"
+		}
+		service = append(service, yaml.MapItem{Key: "image", Value: image})
+
+		if len(s.Command) > 0 {
+			service = append(service, yaml.MapItem{Key: "command", Value: s.Command})
+		}
+		if len(s.Environment) > 0 {
+			env := yaml.MapSlice{}
+			for _, e := range s.Environment {
+				value := e.Value
+				if e.Variable != nil {
+					value = ids[e.Variable]
+				}
+				env = append(env, yaml.MapItem{Key: e.Name, Value: value})
+			}
+			service = append(service, yaml.MapItem{Key: "environment", Value: env})
+		}
+		if len(s.Ports) > 0 {
+			service = append(service, yaml.MapItem{Key: "ports", Value: s.Ports})
+		}
+		if len(s.Volumes) > 0 {
+			volumes := []string{}
+			for _, v := range s.Volumes {
+				source := v.Source
+				if v.Name != "" {
+					source = appName + "-" + v.Name
+				}
+				volumes = append(volumes, paas.VolumeRef(source, v))
+			}
+			service = append(service, yaml.MapItem{Key: "volumes", Value: volumes})
+		}
+		if len(s.DependsOn) > 0 {
+			depends := []string{}
+			for _, name := range s.DependsOn {
+				depends = append(depends, capService(t, name))
+			}
+			service = append(service, yaml.MapItem{Key: "depends_on", Value: depends})
+		}
+		if len(s.CapAdd) > 0 {
+			service = append(service, yaml.MapItem{Key: "cap_add", Value: s.CapAdd})
+		}
+		if s.Restart != "" {
+			service = append(service, yaml.MapItem{Key: "restart", Value: s.Restart})
+		}
+
+		extra := yaml.MapSlice{{Key: "notExposeAsWebApp", Value: "true"}}
+		if s.Name == t.Main && t.Port != 0 {
+			extra = yaml.MapSlice{{Key: "containerHttpPort", Value: strconv.Itoa(int(t.Port))}}
+		}
+		service = append(service, yaml.MapItem{Key: "caproverExtra", Value: extra})
+
+		services = append(services, yaml.MapItem{Key: capService(t, s.Name), Value: service})
+	}
+
+	variables := []yaml.MapSlice{}
+	if t.Version != "" {
+		variables = append(variables, yaml.MapSlice{
+			{Key: "id", Value: "$$cap_version"},
+			{Key: "label", Value: "Version"},
+			{Key: "defaultValue", Value: t.Version},
+			{Key: "description", Value: "Image tag of " + t.Name},
+			{Key: "validRegex", Value: `/^([^\s^\/])+$/`},
+		})
+	}
+	for _, v := range t.Variables {
+		variable := yaml.MapSlice{
+			{Key: "id", Value: ids[v]},
+			{Key: "label", Value: v.Label},
+		}
+		switch {
+		case v.Default != "":
+			variable = append(variable, yaml.MapItem{Key: "defaultValue", Value: v.Default})
+		case v.Secret:
+			variable = append(variable, yaml.MapItem{Key: "defaultValue", Value: "$$cap_gen_random_hex(16)"})
+		}
+		if v.Description != "" {
+			variable = append(variable, yaml.MapItem{Key: "description", Value: v.Description})
+		}
+		if v.Required {
+			variable = append(variable, yaml.MapItem{Key: "validRegex", Value: `/.{1,}/`})
+		}
+		variables = append(variables, variable)
+	}
+
+	end := t.Name + " is deployed."
+	if t.Port != 0 {
+		end += " It is available at http://" + appName + ".$$cap_root_domain"
+	}
+
+	app := yaml.MapSlice{
+		{Key: "variables", Value: variables},
+		{Key: "instructions", Value: yaml.MapSlice{
+			{Key: "start", Value: t.Description},
+			{Key: "end", Value: end},
+		}},
+		{Key: "displayName", Value: t.Name},
+		{Key: "isOfficial", Value: false},
+		{Key: "description", Value: t.Description},
+	}
+	if t.Documentation != "" {
+		app = append(app, yaml.MapItem{Key: "documentation", Value: t.Documentation})
+	}
+
+	return yaml.MapSlice{
+		{Key: "captainVersion", Value: 4},
+		{Key: "services", Value: services},
+		{Key: "caproverOneClickApp", Value: app},
+	}
+}
+
+// capService name, the main service takes the app name
+func capService(t *paas.Template, name string) string {
+	if name == t.Main {
+		return appName
+	}
+
+	return appName + "-" + project.Slugify(name)
+}
+
+// capVariables ids of variables, names only differing in case are suffixed
+// with their order to keep the lowercase ids unique
+func capVariables(variables []*paas.Variable) map[*paas.Variable]string {
+	ids := map[*paas.Variable]string{}
+	used := map[string]bool{}
+	for _, v := range variables {
+		id := "$$cap_" + strings.ToLower(v.Name)
+		for i := 2; used[id]; i++ {
+			id = "$$cap_" + strings.ToLower(v.Name) + "_" + strconv.Itoa(i)
+		}
+		used[id] = true
+		ids[v] = id
+	}
+
+	return ids
+}
@@ -0,0 +1,50 @@
+package caprover
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/modules/paas"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCapRover(t *testing.T) {
+	lower, upper, tz := "", "", "UTC"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Description = "Test app"
+	a.WebUI = &project.WebUI{Scheme: "http", Port: 8080, Path: "/"}
+	a.Parameters = append(a.Parameters,
+		&project.Parameter{Type: project.ParameterTypeVariable, Target: "token", Name: "Token"},
+		&project.Parameter{Type: project.ParameterTypeSecret, Target: "TOKEN", Name: "Admin token"},
+	)
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test:1.0",
+		Environment: types.MappingWithEquals{"token": &lower, "TOKEN": &upper, "TZ": &tz},
+		Ports:       []types.ServicePortConfig{{Target: 8080, Published: 8080}},
+		Volumes:     []types.ServiceVolumeConfig{{Type: "volume", Source: "!data/test/config", Target: "/config"}},
+	})
+
+	res, err := yaml.Marshal(CapRover(paas.NewTemplate(a)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"image: foo/test:$$cap_version",
+		"TOKEN: $$cap_token\n",
+		"token: $$cap_token_2\n",
+		"TZ: UTC",
+		"- $$cap_appname-config:/config",
+		"containerHttpPort: \"8080\"",
+		"id: $$cap_token\n    label: Admin token\n    defaultValue: $$cap_gen_random_hex(16)",
+		"id: $$cap_token_2\n    label: Token",
+	} {
+		if !strings.Contains(string(res), expected) {
+			t.Errorf("%q is missing:\n%s", expected, res)
+		}
+	}
+}
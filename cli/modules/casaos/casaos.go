@@ -0,0 +1,257 @@
+package casaos
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("casaos", Generater)
+}
+
+var (
+	defaultDataPath      = "/DATA/AppData"
+	defaultArchitectures = []string{"amd64", "arm64"}
+	defaultCategory      = "Utilities"
+)
+
+// Text of casaos in multiple languages
+type Text map[string]string
+
+// App is the x-casaos block of compose file
+type App struct {
+	Architectures []string `yaml:"architectures"`
+	Main          string   `yaml:"main"`
+	Author        string   `yaml:"author"`
+	Developer     string   `yaml:"developer"`
+	Category      string   `yaml:"category"`
+	Description   Text     `yaml:"description"`
+	Tagline       Text     `yaml:"tagline"`
+	Title         Text     `yaml:"title"`
+	Icon          string   `yaml:"icon,omitempty"`
+	Index         string   `yaml:"index,omitempty"`
+	PortMap       string   `yaml:"port_map,omitempty"`
+	Scheme        string   `yaml:"scheme,omitempty"`
+	StoreAppID    string   `yaml:"store_app_id"`
+}
+
+// Service is the x-casaos block of service
+type Service struct {
+	Envs    []Description `yaml:"envs,omitempty"`
+	Ports   []Description `yaml:"ports,omitempty"`
+	Volumes []Description `yaml:"volumes,omitempty"`
+}
+
+// Description of env, port or volume
+type Description struct {
+	Container   string `yaml:"container"`
+	Description Text   `yaml:"description"`
+}
+
+// Generater casaos app store tree and its zip
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("data_path", defaultDataPath)
+	o.Config.SetDefault("architectures", defaultArchitectures)
+	o.Config.SetDefault("author", "selfhosted_store")
+	dataPath := strings.TrimSuffix(o.Config.GetString("data_path"), "/")
+	app := App{
+		Architectures: o.Config.GetStringSlice("architectures"),
+		Author:        o.Config.GetString("author"),
+	}
+
+	files := map[string][]byte{}
+	modTimes := map[string]time.Time{}
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		res, err := Convert(a, app, dataPath)
+		if err != nil {
+			return fmt.Errorf("convert application %s to casaos app error: %s", a.Name, err.Error())
+		}
+		files["Apps/"+a.GetID()+"/docker-compose.yml"] = res
+		modTimes["Apps/"+a.GetID()+"/docker-compose.yml"] = ModTime(a)
+
+		if a.IconFile != "" {
+			icon, err := ioutil.ReadFile(a.IconFile)
+			if err != nil {
+				return fmt.Errorf("read icon %s error: %s", a.IconFile, err.Error())
+			}
+			files["Apps/"+a.GetID()+"/icon.png"] = icon
+			modTimes["Apps/"+a.GetID()+"/icon.png"] = ModTime(a)
+		}
+	}
+
+	for name, content := range files {
+		filename := o.Project.GetDistPath("casaos", name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		err := ioutil.WriteFile(filename, content, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	res, err := Archive(files, modTimes)
+	if err != nil {
+		return err
+	}
+
+	filename := o.Project.GetDistPath("casaos", "casaos-appstore.zip")
+	err = ioutil.WriteFile(filename, res, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("write file [%s] error: %s", filename, err.Error())
+	}
+
+	return nil
+}
+
+// ModTime of app files in zip, the last update of app or the zip epoch,
+// which keeps the archive stable between builds
+func ModTime(a *project.Application) time.Time {
+	if a.LastUpdate.IsZero() {
+		return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return a.LastUpdate.UTC()
+}
+
+// Archive files to the casaos-appstore zip, files are sorted by name
+func Archive(files map[string][]byte, modTimes map[string]time.Time) ([]byte, error) {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, name := range names {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     "casaos-appstore/" + name,
+			Method:   zip.Deflate,
+			Modified: modTimes[name],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("zip file [%s] error: %s", name, err.Error())
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			return nil, fmt.Errorf("zip file [%s] error: %s", name, err.Error())
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close zip error: %s", err.Error())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Convert application to casaos compose file, app holds the store wide fields
+func Convert(a *project.Application, app App, dataPath string) ([]byte, error) {
+	id := a.GetID()
+
+	app.Main = a.Services[0].Name
+	app.Developer = app.Author
+	app.Category = defaultCategory
+	if len(a.Category) > 0 && a.Category[0] != "" {
+		app.Category = a.Category[0]
+	}
+	app.Description = Text{"en_us": a.Overview}
+	if a.Overview == "" {
+		app.Description = Text{"en_us": a.Description}
+	}
+	app.Tagline = Text{"en_us": a.Description}
+	app.Title = Text{"en_us": a.Name}
+	app.Icon = a.Icon
+	app.StoreAppID = id
+	if a.WebUI != nil {
+		app.Scheme = a.WebUI.Scheme
+		app.Index = a.WebUI.Path
+		app.PortMap = strconv.Itoa(int(a.GetWebUIPort()))
+	}
+
+	services := types.Services{}
+	for _, s := range a.Services {
+		service := *s
+		x := Service{}
+
+		for _, port := range s.Ports {
+			target := strconv.Itoa(int(port.Target))
+			x.Ports = append(x.Ports, newDescription(a, project.ParameterTypePort, target, "Port "+target))
+		}
+
+		service.Volumes = []types.ServiceVolumeConfig{}
+		for _, v := range s.Volumes {
+			if v.Type != "tmpfs" {
+				switch {
+				case v.Source == project.DataPathPrefix || strings.HasPrefix(v.Source, project.DataPathPrefix+"/"):
+					v.Source = project.ResolveDataPath(v.Source, dataPath)
+				case v.Source == "":
+					v.Source = dataPath + "/" + id + "/" + project.Slugify(v.Target)
+				case !strings.HasPrefix(v.Source, "/"):
+					// named volume
+					v.Source = dataPath + "/" + id + "/" + v.Source
+				}
+				v.Type = "bind"
+				v.Volume = nil
+			}
+			service.Volumes = append(service.Volumes, v)
+			x.Volumes = append(x.Volumes, newDescription(a, project.ParameterTypePath, v.Target, "Path "+v.Target))
+		}
+
+		envs := []string{}
+		for name := range s.Environment {
+			envs = append(envs, name)
+		}
+		sort.Strings(envs)
+		for _, name := range envs {
+			x.Envs = append(x.Envs, newDescription(a, project.ParameterTypeVariable, name, name))
+		}
+
+		service.Extras = map[string]interface{}{"x-casaos": x}
+		services = append(services, service)
+	}
+
+	cfg := &types.Config{
+		Version:  "3.8",
+		Services: services,
+		Extras: map[string]interface{}{
+			"name":     id,
+			"x-casaos": app,
+		},
+	}
+
+	return yaml.Marshal(cfg)
+}
+
+func newDescription(a *project.Application, t, target, fallback string) Description {
+	desc := fallback
+	if p := a.GetParameter(t, target); p != nil {
+		desc = p.GetLabel()
+		if p.Description != "" {
+			desc = p.Description
+		}
+	}
+
+	return Description{
+		Container:   target,
+		Description: Text{"en_us": desc},
+	}
+}
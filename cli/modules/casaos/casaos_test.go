@@ -0,0 +1,83 @@
+package casaos
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestConvertVolumes(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:  "test",
+		Image: "foo/test:1.0",
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: "volume", Source: "!data/test/config", Target: "/config"},
+			{Type: "volume", Source: "!database", Target: "/db"},
+			{Type: "volume", Source: "cache", Target: "/cache"},
+			{Type: "bind", Source: "/etc/localtime", Target: "/etc/localtime"},
+		},
+	})
+
+	res, err := Convert(a, App{}, "/DATA/AppData")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"source: /DATA/AppData/test/config\n",
+		"source: /DATA/AppData/test/!database\n",
+		"source: /DATA/AppData/test/cache\n",
+		"source: /etc/localtime\n",
+	} {
+		if !strings.Contains(string(res), expected) {
+			t.Errorf("%q is missing:\n%s", expected, res)
+		}
+	}
+}
+
+func TestArchiveIsStable(t *testing.T) {
+	a := project.NewApplication()
+	files := map[string][]byte{
+		"Apps/test/docker-compose.yml": []byte("name: test\n"),
+		"Apps/foo/docker-compose.yml":  []byte("name: foo\n"),
+	}
+	modTimes := map[string]time.Time{}
+	for name := range files {
+		modTimes[name] = ModTime(a)
+	}
+
+	first, err := Archive(files, modTimes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Archive(files, modTimes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("archives of the same files differ")
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, f := range r.File {
+		names = append(names, f.Name)
+		if !f.Modified.Equal(time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("%s modified at %s", f.Name, f.Modified)
+		}
+	}
+	expected := "casaos-appstore/Apps/foo/docker-compose.yml,casaos-appstore/Apps/test/docker-compose.yml"
+	if strings.Join(names, ",") != expected {
+		t.Errorf("expected files %s, got %s", expected, strings.Join(names, ","))
+	}
+}
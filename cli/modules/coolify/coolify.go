@@ -0,0 +1,162 @@
+package coolify
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/modules/paas"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("coolify", Generater)
+}
+
+// Generater coolify service templates in the layout of coolify repository,
+// templates/compose/<id>.yaml and svgs/<id>.png
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{}
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		t := paas.NewTemplate(a)
+		logo := ""
+		if a.IconFile != "" {
+			icon, err := ioutil.ReadFile(a.IconFile)
+			if err != nil {
+				return fmt.Errorf("read icon %s error: %s", a.IconFile, err.Error())
+			}
+			logo = "svgs/" + t.ID + ".png"
+			files[logo] = icon
+		}
+
+		res, err := Coolify(t, logo)
+		if err != nil {
+			return fmt.Errorf("marshal coolify template %s error: %s", a.Name, err.Error())
+		}
+		files["templates/compose/"+t.ID+".yaml"] = res
+	}
+
+	return paas.WriteFiles(o, "coolify", files)
+}
+
+// Coolify service template of template, the header comments are the metadata,
+// the web ui is exposed by SERVICE_FQDN and secrets are generated by SERVICE_PASSWORD
+func Coolify(t *paas.Template, logo string) ([]byte, error) {
+	services := yaml.MapSlice{}
+	volumes := yaml.MapSlice{}
+	declared := map[string]bool{}
+
+	for _, s := range t.Services {
+		service := yaml.MapSlice{}
+
+		image := s.Image
+		if s.Name == t.Main && t.Version != "" {
+			image += ":" + t.Version
+		}
+		service = append(service, yaml.MapItem{Key: "image", Value: image})
+
+		if len(s.Command) > 0 {
+			service = append(service, yaml.MapItem{Key: "command", Value: s.Command})
+		}
+
+		env := []string{}
+		if s.Name == t.Main && t.Port != 0 {
+			env = append(env, "SERVICE_FQDN_"+envName(s.Name)+"_"+strconv.Itoa(int(t.Port)))
+		}
+		for _, e := range s.Environment {
+			value := escape(e.Value)
+			if v := e.Variable; v != nil {
+				switch {
+				case v.Secret && v.Default == "":
+					value = "${SERVICE_PASSWORD_" + envName(v.Name) + "}"
+				case v.Required && v.Default == "":
+					value = "${" + v.Name + ":?}"
+				default:
+					value = "${" + v.Name + ":-" + escape(v.Default) + "}"
+				}
+			}
+			env = append(env, e.Name+"="+value)
+		}
+		if len(env) > 0 {
+			service = append(service, yaml.MapItem{Key: "environment", Value: env})
+		}
+
+		if len(s.Ports) > 0 {
+			service = append(service, yaml.MapItem{Key: "ports", Value: s.Ports})
+		}
+		if len(s.Volumes) > 0 {
+			list := []string{}
+			for _, v := range s.Volumes {
+				source := v.Source
+				if v.Name != "" {
+					source = t.ID + "-" + v.Name
+					if !declared[source] {
+						declared[source] = true
+						volumes = append(volumes, yaml.MapItem{Key: source, Value: yaml.MapSlice{}})
+					}
+				}
+				list = append(list, paas.VolumeRef(source, v))
+			}
+			service = append(service, yaml.MapItem{Key: "volumes", Value: list})
+		}
+		if len(s.DependsOn) > 0 {
+			service = append(service, yaml.MapItem{Key: "depends_on", Value: s.DependsOn})
+		}
+		if len(s.CapAdd) > 0 {
+			service = append(service, yaml.MapItem{Key: "cap_add", Value: s.CapAdd})
+		}
+		if s.Restart != "" {
+			service = append(service, yaml.MapItem{Key: "restart", Value: s.Restart})
+		}
+
+		services = append(services, yaml.MapItem{Key: s.Name, Value: service})
+	}
+
+	cfg := yaml.MapSlice{{Key: "services", Value: services}}
+	if len(volumes) > 0 {
+		cfg = append(cfg, yaml.MapItem{Key: "volumes", Value: volumes})
+	}
+
+	res, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{}
+	if t.Documentation != "" {
+		header = append(header, "# documentation: "+t.Documentation)
+	}
+	header = append(header, "# slogan: "+strings.Join(strings.Fields(t.Description), " "))
+	if len(t.Tags) > 0 {
+		header = append(header, "# tags: "+strings.Join(t.Tags, ","))
+	}
+	if logo != "" {
+		header = append(header, "# logo: "+logo)
+	}
+	if t.Port != 0 {
+		header = append(header, "# port: "+strconv.Itoa(int(t.Port)))
+	}
+
+	return append([]byte(strings.Join(header, "\n")+"\n\n"), res...), nil
+}
+
+// envName of coolify magic variables
+func envName(name string) string {
+	return strings.ToUpper(strings.Replace(project.Slugify(name), "-", "_", -1))
+}
+
+// escape $ of literal values from compose interpolation
+func escape(value string) string {
+	return strings.Replace(value, "$", "$$", -1)
+}
@@ -0,0 +1,50 @@
+package coolify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/modules/paas"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestCoolify(t *testing.T) {
+	password, user, hash := "", "", "$2y$05$abc"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Description = "Test\napp"
+	a.Category = []string{"Media"}
+	a.WebUI = &project.WebUI{Scheme: "http", Port: 8080, Path: "/"}
+	a.Parameters = append(a.Parameters,
+		&project.Parameter{Type: project.ParameterTypeSecret, Target: "PASSWORD", Name: "Password"},
+		&project.Parameter{Type: project.ParameterTypeVariable, Target: "USER", Name: "User", Default: "a$b"},
+	)
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test:1.0",
+		Environment: types.MappingWithEquals{"PASSWORD": &password, "USER": &user, "HASH": &hash},
+		Ports:       []types.ServicePortConfig{{Target: 8080, Published: 8080}},
+		Volumes:     []types.ServiceVolumeConfig{{Type: "volume", Source: "!data/test", Target: "/data"}},
+	})
+
+	res, err := Coolify(paas.NewTemplate(a), "svgs/test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"# slogan: Test app\n# tags: media\n# logo: svgs/test.png\n# port: 8080\n\n",
+		"image: foo/test:1.0",
+		"- SERVICE_FQDN_TEST_8080",
+		"- HASH=$$2y$$05$$abc",
+		"- PASSWORD=${SERVICE_PASSWORD_PASSWORD}",
+		"- USER=${USER:-a$$b}",
+		"- test-data:/data",
+		"volumes:\n  test-data: {}",
+	} {
+		if !strings.Contains(string(res), expected) {
+			t.Errorf("%q is missing:\n%s", expected, res)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("dashboard", Generater)
+}
+
+var defaultCategory = "Other"
+
+// Entry of dashboard, an app with web ui
+type Entry struct {
+	Name        string
+	Description string
+	Icon        string
+	URL         string
+	Category    string
+}
+
+// Group of entries by category
+type Group struct {
+	Name    string
+	Entries []*Entry
+}
+
+// HomerConfig is the config.yml of homer
+type HomerConfig struct {
+	Title    string          `yaml:"title"`
+	Subtitle string          `yaml:"subtitle,omitempty"`
+	Services []*HomerService `yaml:"services"`
+}
+
+// HomerService is a group of homer items
+type HomerService struct {
+	Name  string       `yaml:"name"`
+	Items []*HomerItem `yaml:"items"`
+}
+
+// HomerItem of homer
+type HomerItem struct {
+	Name     string `yaml:"name"`
+	Logo     string `yaml:"logo,omitempty"`
+	Subtitle string `yaml:"subtitle,omitempty"`
+	Tag      string `yaml:"tag,omitempty"`
+	URL      string `yaml:"url"`
+	Target   string `yaml:"target"`
+}
+
+// HeimdallItem of heimdall import json
+type HeimdallItem struct {
+	Title       string   `json:"title"`
+	Colour      string   `json:"colour"`
+	URL         string   `json:"url"`
+	Description string   `json:"description,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	Pinned      int      `json:"pinned"`
+	Tags        []string `json:"tags"`
+}
+
+// Generater dashboard configs of homepage, homer and heimdall
+//
+//	title: Selfhosted Store
+//	host: 192.168.1.10
+//	url: https://{id}.home.lan
+//	apps: [jellyfin, nextcloud]
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	if ids := o.Config.GetStringSlice("apps"); len(ids) > 0 {
+		apps, err = Include(apps, ids)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.Config.SetDefault("title", "Selfhosted Store")
+	o.Config.SetDefault("host", "localhost")
+
+	groups := Groups(apps, o.Config.GetString("host"), o.Config.GetString("url"))
+	title := o.Config.GetString("title")
+
+	homepage, err := yaml.Marshal(Homepage(groups))
+	if err != nil {
+		return fmt.Errorf("marshal homepage services error: %s", err.Error())
+	}
+	homer, err := yaml.Marshal(Homer(groups, title))
+	if err != nil {
+		return fmt.Errorf("marshal homer config error: %s", err.Error())
+	}
+	heimdall, err := json.MarshalIndent(Heimdall(groups), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal heimdall items error: %s", err.Error())
+	}
+
+	for name, content := range map[string][]byte{
+		"homepage/services.yaml": homepage,
+		"homer/config.yml":       homer,
+		"heimdall/import.json":   heimdall,
+	} {
+		filename := o.Project.GetDistPath("dashboard", name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		err := ioutil.WriteFile(filename, content, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write dashboard file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Include apps by id in the given order
+func Include(apps []*project.Application, ids []string) ([]*project.Application, error) {
+	index := map[string]*project.Application{}
+	for _, a := range apps {
+		index[a.GetID()] = a
+	}
+
+	result := []*project.Application{}
+	for _, id := range ids {
+		a, ok := index[id]
+		if !ok {
+			return nil, fmt.Errorf("dashboard app [%s] not found", id)
+		}
+		result = append(result, a)
+	}
+
+	return result, nil
+}
+
+// Groups of apps with web ui by the first category,
+// url with {id} placeholder takes priority over the web ui port on host
+func Groups(apps []*project.Application, host, url string) []*Group {
+	groups := []*Group{}
+	index := map[string]*Group{}
+
+	for _, a := range apps {
+		if a.WebUI == nil && url == "" {
+			continue
+		}
+
+		e := &Entry{
+			Name:        a.Name,
+			Description: a.Description,
+			Icon:        a.Icon,
+			URL:         a.GetWebUIURL(host),
+			Category:    defaultCategory,
+		}
+		if url != "" {
+			e.URL = strings.Replace(url, "{id}", a.GetID(), -1)
+		}
+		if len(a.Category) > 0 && a.Category[0] != "" {
+			e.Category = a.Category[0]
+		}
+
+		g, ok := index[e.Category]
+		if !ok {
+			g = &Group{Name: e.Category, Entries: []*Entry{}}
+			index[e.Category] = g
+			groups = append(groups, g)
+		}
+		g.Entries = append(g.Entries, e)
+	}
+
+	return groups
+}
+
+// Homepage services.yaml, a list of groups with a list of services
+func Homepage(groups []*Group) []yaml.MapSlice {
+	services := []yaml.MapSlice{}
+
+	for _, g := range groups {
+		items := []yaml.MapSlice{}
+		for _, e := range g.Entries {
+			item := yaml.MapSlice{{Key: "href", Value: e.URL}}
+			if e.Description != "" {
+				item = append(item, yaml.MapItem{Key: "description", Value: e.Description})
+			}
+			if e.Icon != "" {
+				item = append(item, yaml.MapItem{Key: "icon", Value: e.Icon})
+			}
+			items = append(items, yaml.MapSlice{{Key: e.Name, Value: item}})
+		}
+		services = append(services, yaml.MapSlice{{Key: g.Name, Value: items}})
+	}
+
+	return services
+}
+
+// Homer config.yml
+func Homer(groups []*Group, title string) *HomerConfig {
+	cfg := &HomerConfig{
+		Title:    title,
+		Services: []*HomerService{},
+	}
+
+	for _, g := range groups {
+		service := &HomerService{Name: g.Name, Items: []*HomerItem{}}
+		for _, e := range g.Entries {
+			service.Items = append(service.Items, &HomerItem{
+				Name:     e.Name,
+				Logo:     e.Icon,
+				Subtitle: e.Description,
+				Tag:      strings.ToLower(g.Name),
+				URL:      e.URL,
+				Target:   "_blank",
+			})
+		}
+		cfg.Services = append(cfg.Services, service)
+	}
+
+	return cfg
+}
+
+// Heimdall import items
+func Heimdall(groups []*Group) []*HeimdallItem {
+	items := []*HeimdallItem{}
+
+	for _, g := range groups {
+		for _, e := range g.Entries {
+			items = append(items, &HeimdallItem{
+				Title:       e.Name,
+				Colour:      "#161b1f",
+				URL:         e.URL,
+				Description: e.Description,
+				Icon:        e.Icon,
+				Pinned:      1,
+				Tags:        []string{g.Name},
+			})
+		}
+	}
+
+	return items
+}
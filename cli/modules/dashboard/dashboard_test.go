@@ -0,0 +1,42 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestInclude(t *testing.T) {
+	apps := []*project.Application{}
+	for _, name := range []string{"Jellyfin", "Nextcloud", "Yarr"} {
+		a := project.NewApplication()
+		a.Name = name
+		apps = append(apps, a)
+	}
+
+	tests := []struct {
+		ids  []string
+		want []string
+		err  bool
+	}{
+		{[]string{"yarr", "jellyfin"}, []string{"Yarr", "Jellyfin"}, false},
+		{[]string{"nextcloud"}, []string{"Nextcloud"}, false},
+		{[]string{"missing"}, nil, true},
+	}
+	for _, tt := range tests {
+		result, err := Include(apps, tt.ids)
+		if (err != nil) != tt.err {
+			t.Errorf("Include(%v) error = %v, want error %v", tt.ids, err, tt.err)
+			continue
+		}
+		if len(result) != len(tt.want) {
+			t.Errorf("Include(%v) = %d apps, want %v", tt.ids, len(result), tt.want)
+			continue
+		}
+		for i, name := range tt.want {
+			if result[i].Name != name {
+				t.Errorf("Include(%v)[%d] = %s, want %s", tt.ids, i, result[i].Name, name)
+			}
+		}
+	}
+}
@@ -71,8 +71,8 @@ func LoadApplication(a *project.Application, payload []byte) error {
 		return fmt.Errorf("load docker compose services error: no service found")
 	}
 
-	for _, service := range config.Services {
-		a.Services = append(a.Services, &service)
+	for i := range config.Services {
+		a.Services = append(a.Services, &config.Services[i])
 	}
 
 	return nil
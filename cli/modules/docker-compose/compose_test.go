@@ -0,0 +1,30 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestLoadApplicationServices(t *testing.T) {
+	payload := []byte(`version: "3"
+services:
+  app:
+    image: foo/app:1.0
+  db:
+    image: postgres:13
+`)
+
+	a := project.NewApplication()
+	if err := LoadApplication(a, payload); err != nil {
+		t.Fatal(err)
+	}
+
+	images := map[string]string{}
+	for _, s := range a.Services {
+		images[s.Name] = s.Image
+	}
+	if len(a.Services) != 2 || images["app"] != "foo/app:1.0" || images["db"] != "postgres:13" {
+		t.Errorf("services are not loaded one by one: %v", images)
+	}
+}
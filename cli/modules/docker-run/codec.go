@@ -0,0 +1,54 @@
+package dockerrun
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterEncoder("docker-run", Encoder)
+	project.RegisterGenerater("docker-run", Generater)
+}
+
+// Encoder for docker run commands
+func Encoder(a *project.Application) ([]byte, error) {
+	if len(a.Services) == 0 {
+		return nil, fmt.Errorf("no service found")
+	}
+
+	return []byte(strings.Join(Commands(a, Option{}), "\n") + "\n"), nil
+}
+
+// Generater docker run scripts, one shell script per app
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	opt := Option{
+		DataPath: o.Config.GetString("data_path"),
+	}
+
+	path := o.Project.GetDistPath("templates", "docker-run")
+	os.MkdirAll(path, os.ModePerm)
+
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		script := "#!/bin/sh\n# " + a.Name + "\n" + strings.Join(Commands(a, opt), "\n") + "\n"
+		filename := path + "/" + a.GetID() + ".sh"
+		err = ioutil.WriteFile(filename, []byte(script), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write script file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
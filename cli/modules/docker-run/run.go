@@ -0,0 +1,186 @@
+package dockerrun
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// DefaultDataPath replace the data path prefix of volumes
+var DefaultDataPath = "/opt/appdata"
+
+var safeShellPattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// Option for docker run commands
+type Option struct {
+	DataPath string
+	// Network is the user defined network of multi services,
+	// default is created when the app has more than one service
+	Network string
+}
+
+// Quote string for posix shell
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if safeShellPattern.MatchString(s) {
+		return s
+	}
+
+	return "'" + strings.Replace(s, "'", `'"'"'`, -1) + "'"
+}
+
+// Commands of application, one line per command
+func Commands(a *project.Application, opt Option) []string {
+	if opt.DataPath == "" {
+		opt.DataPath = DefaultDataPath
+	}
+	if opt.Network == "" && len(a.Services) > 1 {
+		opt.Network = a.GetID()
+	}
+
+	commands := []string{}
+	if opt.Network != "" {
+		commands = append(commands, "docker network create "+Quote(opt.Network))
+	}
+
+	for _, service := range a.Services {
+		commands = append(commands, joinArgs(ServiceArgs(service, opt)))
+	}
+
+	return commands
+}
+
+// ServiceArgs of docker run command for service
+func ServiceArgs(service *types.ServiceConfig, opt Option) []string {
+	args := []string{"docker", "run", "-d"}
+
+	name := service.ContainerName
+	if name == "" {
+		name = service.Name
+	}
+	if name != "" {
+		args = append(args, "--name", name)
+	}
+
+	if service.Restart != "" && service.Restart != "no" {
+		args = append(args, "--restart", service.Restart)
+	}
+
+	if service.NetworkMode != "" {
+		args = append(args, "--network", service.NetworkMode)
+	} else if opt.Network != "" {
+		args = append(args, "--network", opt.Network)
+		if service.Name != "" {
+			args = append(args, "--network-alias", service.Name)
+		}
+	}
+
+	if service.Hostname != "" {
+		args = append(args, "--hostname", service.Hostname)
+	}
+	if service.User != "" {
+		args = append(args, "--user", service.User)
+	}
+	if service.WorkingDir != "" {
+		args = append(args, "--workdir", service.WorkingDir)
+	}
+	if service.Privileged {
+		args = append(args, "--privileged")
+	}
+
+	for _, port := range service.Ports {
+		args = append(args, "-p", formatPort(port))
+	}
+
+	for _, volume := range service.Volumes {
+		if volume.Type == "tmpfs" {
+			args = append(args, "--tmpfs", volume.Target)
+			continue
+		}
+
+		v := project.ResolveDataPath(volume.Source, opt.DataPath) + ":" + volume.Target
+		if volume.Source == "" {
+			v = volume.Target
+		}
+		if volume.ReadOnly {
+			v += ":ro"
+		}
+		args = append(args, "-v", v)
+	}
+
+	for _, name := range sortedKeys(service.Environment) {
+		if value := service.Environment[name]; value != nil {
+			args = append(args, "-e", name+"="+*value)
+		} else {
+			args = append(args, "-e", name)
+		}
+	}
+
+	for _, c := range service.CapAdd {
+		args = append(args, "--cap-add", c)
+	}
+	for _, c := range service.CapDrop {
+		args = append(args, "--cap-drop", c)
+	}
+	for _, d := range service.Devices {
+		args = append(args, "--device", d)
+	}
+
+	labels := []string{}
+	for name := range service.Labels {
+		labels = append(labels, name)
+	}
+	sort.Strings(labels)
+	for _, name := range labels {
+		args = append(args, "-l", name+"="+service.Labels[name])
+	}
+
+	if len(service.Entrypoint) > 0 {
+		args = append(args, "--entrypoint", service.Entrypoint[0])
+	}
+
+	args = append(args, service.Image)
+	if len(service.Entrypoint) > 1 {
+		args = append(args, service.Entrypoint[1:]...)
+	}
+	args = append(args, service.Command...)
+
+	return args
+}
+
+func formatPort(port types.ServicePortConfig) string {
+	p := strconv.Itoa(int(port.Target))
+	if port.Published != 0 {
+		p = strconv.Itoa(int(port.Published)) + ":" + p
+	}
+	if port.Protocol != "" && port.Protocol != "tcp" {
+		p += "/" + port.Protocol
+	}
+
+	return p
+}
+
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func sortedKeys(m types.MappingWithEquals) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
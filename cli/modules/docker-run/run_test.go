@@ -0,0 +1,82 @@
+package dockerrun
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestQuote(t *testing.T) {
+	cases := map[string]string{
+		"":                  "''",
+		"foo/bar:1.0":       "foo/bar:1.0",
+		"TZ=UTC":            "TZ=UTC",
+		"hello world":       "'hello world'",
+		"it's":              `'it'"'"'s'`,
+		"$HOME":             "'$HOME'",
+		"a;rm -rf /":        "'a;rm -rf /'",
+		"/opt/appdata:/cfg": "/opt/appdata:/cfg",
+	}
+
+	for s, expected := range cases {
+		if res := Quote(s); res != expected {
+			t.Errorf("quote %q expected %s, got %s", s, expected, res)
+		}
+	}
+}
+
+func TestServiceArgs(t *testing.T) {
+	tz := "UTC"
+	service := &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test:1.0",
+		Restart:     "unless-stopped",
+		Environment: types.MappingWithEquals{"TZ": &tz, "PUID": nil},
+		Ports:       []types.ServicePortConfig{{Target: 8080, Published: 8081}, {Target: 53, Protocol: "udp"}},
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: "volume", Source: "!data/test", Target: "/config"},
+			{Type: "bind", Source: "/etc/localtime", Target: "/etc/localtime", ReadOnly: true},
+			{Type: "tmpfs", Target: "/tmp"},
+		},
+		Labels:     types.Labels{"b": "2", "a": "1"},
+		Entrypoint: types.ShellCommand{"/init", "--verbose"},
+		Command:    types.ShellCommand{"serve"},
+	}
+
+	args := ServiceArgs(service, Option{DataPath: "/opt/appdata", Network: "test"})
+
+	expected := []string{
+		"docker", "run", "-d", "--name", "test", "--restart", "unless-stopped",
+		"--network", "test", "--network-alias", "test",
+		"-p", "8081:8080", "-p", "53/udp",
+		"-v", "/opt/appdata/test:/config", "-v", "/etc/localtime:/etc/localtime:ro", "--tmpfs", "/tmp",
+		"-e", "PUID", "-e", "TZ=UTC",
+		"-l", "a=1", "-l", "b=2",
+		"--entrypoint", "/init", "foo/test:1.0", "--verbose", "serve",
+	}
+	if strings.Join(args, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected args\n%s\ngot\n%s", strings.Join(expected, " "), strings.Join(args, " "))
+	}
+}
+
+func TestCommands(t *testing.T) {
+	msg := "hello world"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services,
+		&types.ServiceConfig{Name: "app", Image: "foo/app", Environment: types.MappingWithEquals{"MSG": &msg}},
+		&types.ServiceConfig{Name: "db", Image: "postgres"},
+	)
+
+	expected := []string{
+		"docker network create test",
+		"docker run -d --name app --network test --network-alias app -e 'MSG=hello world' foo/app",
+		"docker run -d --name db --network test --network-alias db postgres",
+	}
+	res := Commands(a, Option{})
+	if strings.Join(res, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(res, "\n"))
+	}
+}
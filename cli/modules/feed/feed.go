@@ -0,0 +1,272 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("feed", Generater)
+}
+
+// Atom feed
+type Atom struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Updated string       `xml:"updated"`
+	Author  AtomAuthor   `xml:"author"`
+	Link    []AtomLink   `xml:"link"`
+	Entries []*AtomEntry `xml:"entry"`
+}
+
+// AtomAuthor of feed, entries inherit it
+type AtomAuthor struct {
+	Name  string `xml:"name"`
+	URI   string `xml:"uri,omitempty"`
+	Email string `xml:"email,omitempty"`
+}
+
+// AtomLink of feed and entry
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+// AtomEntry of atom feed
+type AtomEntry struct {
+	Title    string   `xml:"title"`
+	ID       string   `xml:"id"`
+	Updated  string   `xml:"updated"`
+	Link     AtomLink `xml:"link"`
+	Category struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+	Summary string `xml:"summary"`
+}
+
+// RSS feed
+type RSS struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+// RSSChannel of rss feed
+type RSSChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate"`
+	Items         []*RSSItem `xml:"item"`
+}
+
+// RSSItem of rss feed
+type RSSItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        RSSGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category"`
+	Description string  `xml:"description"`
+}
+
+// RSSGUID of item, the entry id is not a link
+type RSSGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+// Generater atom and rss feeds of added, updated and removed apps
+//
+//	title: Selfhosted Store
+//	author: Selfhosted Store
+//	email: someone@example.com
+//	url: https://example.com/store/
+//	link: app/{id}.html
+//	snapshot: https://example.com/store/feed/snapshot.json
+//	days: 30
+//	limit: 50
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("title", "Selfhosted Store")
+	o.Config.SetDefault("link", "app/{id}.html")
+	o.Config.SetDefault("days", 30)
+	o.Config.SetDefault("limit", 50)
+
+	baseURL := o.Config.GetString("url")
+	if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	// dist is cleaned before builds, the previous snapshot is the deployed one
+	if baseURL != "" {
+		o.Config.SetDefault("snapshot", baseURL+"feed/snapshot.json")
+	} else {
+		o.Config.SetDefault("snapshot", o.Project.GetDistPath("feed", "snapshot.json"))
+	}
+	o.Config.SetDefault("author", o.Config.GetString("title"))
+
+	now := time.Now().UTC()
+	cur := NewSnapshot(apps, now)
+
+	prev, err := LoadSnapshot(o.Config.GetString("snapshot"))
+	if err != nil {
+		return err
+	}
+
+	if prev != nil {
+		cur.Entries = append(Compare(prev, cur), prev.Entries...)
+	} else {
+		cur.Entries = Recent(apps, now, o.Config.GetInt("days"))
+	}
+	if limit := o.Config.GetInt("limit"); limit > 0 && len(cur.Entries) > limit {
+		cur.Entries = cur.Entries[:limit]
+	}
+
+	link := func(id string) string {
+		return baseURL + strings.Replace(o.Config.GetString("link"), "{id}", id, -1)
+	}
+	title := o.Config.GetString("title")
+
+	atom := &Atom{
+		Title:   title,
+		ID:      baseURL + "feed/atom.xml",
+		Updated: now.Format(time.RFC3339),
+		Author: AtomAuthor{
+			Name:  o.Config.GetString("author"),
+			URI:   baseURL,
+			Email: o.Config.GetString("email"),
+		},
+		Link: []AtomLink{
+			{Href: baseURL + "feed/atom.xml", Rel: "self"},
+			{Href: baseURL},
+		},
+		Entries: []*AtomEntry{},
+	}
+	rss := &RSS{
+		Version: "2.0",
+		Channel: RSSChannel{
+			Title:         title,
+			Link:          baseURL,
+			Description:   "New and updated apps of " + title,
+			LastBuildDate: now.Format(time.RFC1123Z),
+			Items:         []*RSSItem{},
+		},
+	}
+
+	for _, e := range cur.Entries {
+		entryTitle := e.Name + " " + e.Kind
+		entry := &AtomEntry{
+			Title:   entryTitle,
+			ID:      baseURL + "feed/" + e.ID,
+			Updated: e.Time.UTC().Format(time.RFC3339),
+			Link:    AtomLink{Href: link(e.App)},
+			Summary: e.Summary,
+		}
+		entry.Category.Term = e.Kind
+		atom.Entries = append(atom.Entries, entry)
+
+		rss.Channel.Items = append(rss.Channel.Items, &RSSItem{
+			Title:       entryTitle,
+			Link:        link(e.App),
+			GUID:        RSSGUID{Value: baseURL + "feed/" + e.ID, IsPermaLink: "false"},
+			PubDate:     e.Time.UTC().Format(time.RFC1123Z),
+			Category:    e.Kind,
+			Description: e.Summary,
+		})
+	}
+
+	path := o.Project.GetDistPath("feed")
+	os.MkdirAll(path, os.ModePerm)
+
+	for name, v := range map[string]interface{}{"atom.xml": atom, "rss.xml": rss} {
+		res, err := xml.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal feed %s error: %s", name, err.Error())
+		}
+
+		filename := path + "/" + name
+		err = ioutil.WriteFile(filename, append([]byte(xml.Header), res...), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write feed file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	res, err := json.MarshalIndent(cur, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal snapshot error: %s", err.Error())
+	}
+	filename := path + "/snapshot.json"
+	err = ioutil.WriteFile(filename, res, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("write snapshot file [%s] error: %s", filename, err.Error())
+	}
+
+	return nil
+}
+
+// LoadSnapshot of previous build from file or url, nil when not exist,
+// an unreachable url is not an error, the history starts again
+func LoadSnapshot(filename string) (*Snapshot, error) {
+	if strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://") {
+		return fetchSnapshot(filename)
+	}
+
+	payload, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read snapshot %s error: %s", filename, err.Error())
+	}
+
+	return parseSnapshot(filename, payload)
+}
+
+func fetchSnapshot(url string) (*Snapshot, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Printf("feed generater: fetch snapshot %s error: %s, changes are taken from app timestamps\n", url, err.Error())
+		return nil, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode != http.StatusNotFound {
+			fmt.Printf("feed generater: fetch snapshot %s error: status %s, changes are taken from app timestamps\n", url, resp.Status)
+		}
+		return nil, nil
+	}
+
+	payload, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("feed generater: read snapshot %s error: %s, changes are taken from app timestamps\n", url, err.Error())
+		return nil, nil
+	}
+
+	return parseSnapshot(url, payload)
+}
+
+func parseSnapshot(filename string, payload []byte) (*Snapshot, error) {
+	s := &Snapshot{}
+	err := json.Unmarshal(payload, s)
+	if err != nil {
+		return nil, fmt.Errorf("parse snapshot %s error: %s", filename, err.Error())
+	}
+
+	return s, nil
+}
@@ -0,0 +1,93 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSnapshotURL(t *testing.T) {
+	prev := &Snapshot{
+		Generated: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Apps:      map[string]*Item{"yarr": {Name: "Yarr", Images: []string{"docker.io/yangkghjh/yarr:1.0"}}},
+		Entries:   []*Entry{},
+	}
+	payload, _ := json.Marshal(prev)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed/snapshot.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	s, err := LoadSnapshot(server.URL + "/feed/snapshot.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s == nil || s.Apps["yarr"] == nil {
+		t.Fatalf("snapshot not loaded: %+v", s)
+	}
+
+	for _, url := range []string{
+		server.URL + "/missing.json",
+		"http://127.0.0.1:1/feed/snapshot.json",
+	} {
+		s, err = LoadSnapshot(url)
+		if err != nil || s != nil {
+			t.Errorf("snapshot of %s = %+v, %v, want nil, nil", url, s, err)
+		}
+	}
+}
+
+func TestRSSGUID(t *testing.T) {
+	item := &RSSItem{Title: "Yarr added", GUID: RSSGUID{Value: "feed/yarr-added", IsPermaLink: "false"}}
+	res, err := xml.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(res), `<guid isPermaLink="false">feed/yarr-added</guid>`) {
+		t.Errorf("guid is a permalink: %s", res)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+	prev := &Snapshot{Apps: map[string]*Item{
+		"yarr": {Name: "Yarr", Images: []string{"docker.io/yangkghjh/yarr:1.0"}},
+		"old":  {Name: "Old", Images: []string{"docker.io/foo/old:latest"}},
+	}}
+	cur := &Snapshot{Generated: now, Apps: map[string]*Item{
+		"yarr": {Name: "Yarr", Images: []string{"docker.io/yangkghjh/yarr:1.1"}},
+		"new":  {Name: "New", Images: []string{"docker.io/foo/new:latest"}},
+	}}
+
+	kinds := map[string]string{}
+	for _, e := range Compare(prev, cur) {
+		kinds[e.App] = e.Kind
+	}
+
+	want := map[string]string{"yarr": KindUpdated, "new": KindAdded, "old": KindRemoved}
+	for app, kind := range want {
+		if kinds[app] != kind {
+			t.Errorf("%s: kind = %q, want %q", app, kinds[app], kind)
+		}
+	}
+}
+
+func TestAtomAuthor(t *testing.T) {
+	atom := &Atom{Title: "Store", Author: AtomAuthor{Name: "Store"}}
+	res, err := xml.Marshal(atom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(res), "<author><name>Store</name></author>") {
+		t.Errorf("feed has no author: %s", res)
+	}
+}
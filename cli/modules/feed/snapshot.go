@@ -0,0 +1,219 @@
+package feed
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// Kinds of change
+const (
+	KindAdded   = "added"
+	KindUpdated = "updated"
+	KindRemoved = "removed"
+)
+
+// Snapshot of catalog, kept in dist for the next build to compare,
+// Entries are the feed history, newest first
+type Snapshot struct {
+	Generated time.Time        `json:"generated"`
+	Apps      map[string]*Item `json:"apps"`
+	Entries   []*Entry         `json:"entries"`
+}
+
+// Item of app in snapshot
+type Item struct {
+	Name       string    `json:"name"`
+	Images     []string  `json:"images"`
+	Ports      []string  `json:"ports"`
+	Parameters []string  `json:"parameters"`
+	LastUpdate time.Time `json:"last_update,omitempty"`
+}
+
+// Entry of feed
+type Entry struct {
+	ID      string    `json:"id"`
+	Kind    string    `json:"kind"`
+	App     string    `json:"app"`
+	Name    string    `json:"name"`
+	Summary string    `json:"summary"`
+	Time    time.Time `json:"time"`
+}
+
+// NewSnapshot of applications
+func NewSnapshot(apps []*project.Application, now time.Time) *Snapshot {
+	s := &Snapshot{
+		Generated: now,
+		Apps:      map[string]*Item{},
+		Entries:   []*Entry{},
+	}
+
+	for _, a := range apps {
+		s.Apps[a.GetID()] = NewItem(a)
+	}
+
+	return s
+}
+
+// NewItem of application
+func NewItem(a *project.Application) *Item {
+	item := &Item{
+		Name:       a.Name,
+		Images:     []string{},
+		Ports:      []string{},
+		Parameters: []string{},
+		LastUpdate: a.LastUpdate,
+	}
+
+	for _, service := range a.Services {
+		item.Images = append(item.Images, project.ParseImage(service.Image).String())
+		for _, port := range service.Ports {
+			p := strconv.Itoa(int(port.Target))
+			if port.Protocol != "" && port.Protocol != "tcp" {
+				p += "/" + port.Protocol
+			}
+			item.Ports = append(item.Ports, p)
+		}
+	}
+	for _, p := range a.Parameters {
+		item.Parameters = append(item.Parameters, p.GetLabel())
+	}
+
+	return item
+}
+
+// Compare current snapshot with the previous one, entries of changes are returned
+func Compare(prev, cur *Snapshot) []*Entry {
+	entries := []*Entry{}
+
+	for _, id := range sortedIDs(cur.Apps) {
+		item := cur.Apps[id]
+		old, ok := prev.Apps[id]
+		if !ok {
+			entries = append(entries, newEntry(KindAdded, id, item.Name, "New app "+item.Name+" "+strings.Join(item.Images, ", "), cur.Generated))
+			continue
+		}
+
+		if changes := diff(old, item); len(changes) > 0 {
+			entries = append(entries, newEntry(KindUpdated, id, item.Name, strings.Join(changes, "; "), cur.Generated))
+		}
+	}
+
+	for _, id := range sortedIDs(prev.Apps) {
+		if _, ok := cur.Apps[id]; !ok {
+			name := prev.Apps[id].Name
+			entries = append(entries, newEntry(KindRemoved, id, name, name+" is removed from the store", cur.Generated))
+		}
+	}
+
+	return entries
+}
+
+// Recent entries by per app timestamps, for the builds without previous snapshot
+func Recent(apps []*project.Application, now time.Time, days int) []*Entry {
+	entries := []*Entry{}
+
+	for _, a := range apps {
+		id := a.GetID()
+		item := NewItem(a)
+		switch {
+		case a.IsNew(now, days):
+			entries = append(entries, newEntry(KindAdded, id, a.Name, "New app "+a.Name+" "+strings.Join(item.Images, ", "), a.FirstSeen))
+		case a.IsUpdated(now, days):
+			summary := a.Changes
+			if summary == "" {
+				summary = a.Name + " is updated, " + strings.Join(item.Images, ", ")
+			}
+			entries = append(entries, newEntry(KindUpdated, id, a.Name, summary, a.LastUpdate))
+		}
+	}
+
+	sortEntries(entries)
+	return entries
+}
+
+// diff of items, in human readable sentences
+func diff(old, cur *Item) []string {
+	changes := []string{}
+
+	oldTags := map[string]string{}
+	for _, ref := range old.Images {
+		image := project.ParseImage(ref)
+		oldTags[image.Name()] = image.Tag
+	}
+	for _, ref := range cur.Images {
+		image := project.ParseImage(ref)
+		tag, ok := oldTags[image.Name()]
+		switch {
+		case !ok:
+			changes = append(changes, "new image "+image.String())
+		case tag != image.Tag:
+			changes = append(changes, "image "+image.Name()+" tag "+tag+" -> "+image.Tag)
+		}
+	}
+
+	if added := subtract(cur.Ports, old.Ports); len(added) > 0 {
+		changes = append(changes, "new ports "+strings.Join(added, ", "))
+	}
+	if removed := subtract(old.Ports, cur.Ports); len(removed) > 0 {
+		changes = append(changes, "removed ports "+strings.Join(removed, ", "))
+	}
+	if added := subtract(cur.Parameters, old.Parameters); len(added) > 0 {
+		changes = append(changes, "new parameters "+strings.Join(added, ", "))
+	}
+	if removed := subtract(old.Parameters, cur.Parameters); len(removed) > 0 {
+		changes = append(changes, "removed parameters "+strings.Join(removed, ", "))
+	}
+	if len(changes) == 0 && cur.LastUpdate.After(old.LastUpdate) && !old.LastUpdate.IsZero() {
+		changes = append(changes, "template updated")
+	}
+
+	return changes
+}
+
+func subtract(a, b []string) []string {
+	m := map[string]bool{}
+	for _, s := range b {
+		m[s] = true
+	}
+
+	res := []string{}
+	for _, s := range a {
+		if !m[s] {
+			res = append(res, s)
+			m[s] = true
+		}
+	}
+
+	return res
+}
+
+func newEntry(kind, id, name, summary string, t time.Time) *Entry {
+	return &Entry{
+		ID:      id + "/" + kind + "/" + strconv.FormatInt(t.Unix(), 10),
+		Kind:    kind,
+		App:     id,
+		Name:    name,
+		Summary: summary,
+		Time:    t,
+	}
+}
+
+func sortEntries(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+}
+
+func sortedIDs(m map[string]*Item) []string {
+	ids := []string{}
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
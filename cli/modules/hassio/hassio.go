@@ -0,0 +1,313 @@
+package hassio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("hassio", Generater)
+}
+
+var defaultArch = []string{"aarch64", "amd64", "armv7"}
+
+// map types of add-on, assigned to the data volumes in order,
+// /data of add-on is always mapped
+var mapTypes = []string{"addon_config", "share", "media"}
+
+// Repository is the repository.yaml of add-on repository
+type Repository struct {
+	Name       string `yaml:"name"`
+	URL        string `yaml:"url,omitempty"`
+	Maintainer string `yaml:"maintainer,omitempty"`
+}
+
+// Config is the config.yaml of add-on
+type Config struct {
+	Name             string            `yaml:"name"`
+	Version          string            `yaml:"version"`
+	Slug             string            `yaml:"slug"`
+	Description      string            `yaml:"description"`
+	URL              string            `yaml:"url,omitempty"`
+	Arch             []string          `yaml:"arch"`
+	Image            string            `yaml:"image"`
+	Startup          string            `yaml:"startup"`
+	Boot             string            `yaml:"boot"`
+	Init             bool              `yaml:"init"`
+	HostNetwork      bool              `yaml:"host_network,omitempty"`
+	Privileged       []string          `yaml:"privileged,omitempty"`
+	Devices          []string          `yaml:"devices,omitempty"`
+	WebUI            string            `yaml:"webui,omitempty"`
+	Ports            map[string]int    `yaml:"ports,omitempty"`
+	PortsDescription map[string]string `yaml:"ports_description,omitempty"`
+	Map              []*Map            `yaml:"map,omitempty"`
+	Environment      map[string]string `yaml:"environment,omitempty"`
+	Options          yaml.MapSlice     `yaml:"options"`
+	Schema           yaml.MapSlice     `yaml:"schema"`
+
+	// Unmapped volumes have no host folder in add-on
+	Unmapped []string `yaml:"-"`
+}
+
+// Map of add-on folder
+type Map struct {
+	Type     string `yaml:"type"`
+	ReadOnly bool   `yaml:"read_only"`
+	Path     string `yaml:"path,omitempty"`
+}
+
+// Generater home assistant add-on repository of supported apps
+//
+//	name: Selfhosted Store
+//	url: https://github.com/yankghjh/selfhosted_store
+//	maintainer: yankghjh
+//	arch: [aarch64, amd64, armv7]
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("name", "Selfhosted Store")
+	o.Config.SetDefault("arch", defaultArch)
+	arch := o.Config.GetStringSlice("arch")
+
+	files := map[string][]byte{}
+
+	res, err := yaml.Marshal(&Repository{
+		Name:       o.Config.GetString("name"),
+		URL:        o.Config.GetString("url"),
+		Maintainer: o.Config.GetString("maintainer"),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal repository error: %s", err.Error())
+	}
+	files["repository.yaml"] = res
+
+	for _, a := range apps {
+		if !Supported(a) {
+			continue
+		}
+
+		cfg := Convert(a, arch)
+		res, err := yaml.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("marshal add-on %s config error: %s", a.Name, err.Error())
+		}
+		files[cfg.Slug+"/config.yaml"] = res
+		files[cfg.Slug+"/DOCS.md"] = []byte(Docs(a, cfg))
+
+		if a.IconFile != "" {
+			icon, err := ioutil.ReadFile(a.IconFile)
+			if err != nil {
+				return fmt.Errorf("read icon %s error: %s", a.IconFile, err.Error())
+			}
+			files[cfg.Slug+"/icon.png"] = icon
+		}
+	}
+
+	for name, content := range files {
+		filename := o.Project.GetDistPath("hassio", name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		err := ioutil.WriteFile(filename, content, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write add-on file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Supported application of add-on, a single service
+func Supported(a *project.Application) bool {
+	return len(a.Services) == 1
+}
+
+// Convert single service application to add-on config, options and schema
+// are derived from the environment parameters, the other variables are fixed
+// in environment, secrets never have a default option
+func Convert(a *project.Application, arch []string) *Config {
+	service := a.Services[0]
+	image := project.ParseImage(service.Image)
+
+	cfg := &Config{
+		Name:             a.Name,
+		Version:          image.Tag,
+		Slug:             strings.Replace(a.GetID(), "-", "_", -1),
+		Description:      a.Description,
+		Arch:             arch,
+		Image:            strings.TrimPrefix(image.Name(), project.DefaultRegistry+"/"),
+		Startup:          "application",
+		Boot:             "auto",
+		HostNetwork:      service.NetworkMode == "host",
+		Privileged:       service.CapAdd,
+		Ports:            map[string]int{},
+		PortsDescription: map[string]string{},
+		Map:              []*Map{},
+		Environment:      map[string]string{},
+		Options:          yaml.MapSlice{},
+		Schema:           yaml.MapSlice{},
+	}
+	if cfg.Version == "" {
+		cfg.Version = project.DefaultTag
+	}
+	if l := a.GetLink(project.LinkTypeProject); l != nil {
+		cfg.URL = l.URL
+	}
+
+	for _, d := range service.Devices {
+		cfg.Devices = append(cfg.Devices, strings.SplitN(d, ":", 2)[0])
+	}
+
+	for _, port := range service.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		key := strconv.Itoa(int(port.Target)) + "/" + protocol
+		published := port.Published
+		if published == 0 {
+			published = port.Target
+		}
+		cfg.Ports[key] = int(published)
+
+		description := key
+		if p := a.GetParameter(project.ParameterTypePort, strconv.Itoa(int(port.Target))); p != nil {
+			description = p.GetLabel()
+		} else if a.WebUI != nil && a.WebUI.Port == port.Target {
+			description = "Web UI"
+		}
+		cfg.PortsDescription[key] = description
+	}
+	if a.WebUI != nil {
+		path := a.WebUI.Path
+		if path == "" {
+			path = "/"
+		}
+		cfg.WebUI = a.WebUI.Scheme + "://[HOST]:[PORT:" + strconv.Itoa(int(a.WebUI.Port)) + "]" + path
+	}
+
+	for _, v := range service.Volumes {
+		if !strings.HasPrefix(v.Source, project.DataPathPrefix) {
+			if v.Type != "tmpfs" {
+				cfg.Unmapped = append(cfg.Unmapped, v.Target)
+			}
+			continue
+		}
+		if v.Target == "/data" {
+			continue
+		}
+		if len(cfg.Map) == len(mapTypes) {
+			cfg.Unmapped = append(cfg.Unmapped, v.Target)
+			continue
+		}
+		cfg.Map = append(cfg.Map, &Map{Type: mapTypes[len(cfg.Map)], ReadOnly: v.ReadOnly, Path: v.Target})
+	}
+
+	names := []string{}
+	for name := range service.Environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := ""
+		if v := service.Environment[name]; v != nil {
+			value = *v
+		}
+
+		p := parameter(a, name)
+		if p == nil {
+			cfg.Environment[name] = value
+			continue
+		}
+
+		if p.Default != "" {
+			value = p.Default
+		}
+		schema := "str"
+		if p.IsSecret() {
+			schema = "password"
+			value = ""
+		}
+		if !p.Required {
+			schema += "?"
+		}
+		cfg.Options = append(cfg.Options, yaml.MapItem{Key: name, Value: value})
+		cfg.Schema = append(cfg.Schema, yaml.MapItem{Key: name, Value: schema})
+	}
+
+	return cfg
+}
+
+// Docs of add-on in markdown
+func Docs(a *project.Application, cfg *Config) string {
+	b := &strings.Builder{}
+
+	b.WriteString("# " + a.Name + "\n\n")
+	if a.Description != "" {
+		b.WriteString(a.Description + "\n\n")
+	}
+	if a.Overview != "" {
+		b.WriteString(a.Overview + "\n\n")
+	}
+
+	if len(cfg.Options) > 0 {
+		b.WriteString("## Configuration\n\n")
+		b.WriteString("Options are saved to `/data/options.json` of the add-on, they are not passed to the container as environment variables. ")
+		b.WriteString("The image reads its settings from the environment, so the options below only document the variables, ")
+		b.WriteString("the defaults of the image are used until it reads the options file.\n\n")
+		b.WriteString("| Option | Description |\n| --- | --- |\n")
+		for _, item := range cfg.Options {
+			name := item.Key.(string)
+			description := ""
+			if p := parameter(a, name); p != nil {
+				description = p.Description
+				if description == "" {
+					description = p.Name
+				}
+			}
+			b.WriteString("| `" + name + "` | " + description + " |\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(cfg.Map) > 0 || len(cfg.Unmapped) > 0 {
+		b.WriteString("## Storage\n\n")
+		for _, m := range cfg.Map {
+			b.WriteString("- `" + m.Path + "` is stored in the `" + m.Type + "` folder.\n")
+		}
+		for _, target := range cfg.Unmapped {
+			b.WriteString("- `" + target + "` is not mapped to a host folder.\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(a.Links) > 0 {
+		b.WriteString("## Links\n\n")
+		for _, l := range a.Links {
+			if l.Type == "" || l.URL == "" {
+				continue
+			}
+			b.WriteString("- " + strings.ToUpper(l.Type[:1]) + l.Type[1:] + ": " + l.URL + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// parameter of environment, secret or variable
+func parameter(a *project.Application, name string) *project.Parameter {
+	if p := a.GetParameter(project.ParameterTypeSecret, name); p != nil {
+		return p
+	}
+
+	return a.GetParameter(project.ParameterTypeVariable, name)
+}
@@ -0,0 +1,71 @@
+package hassio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"github.com/yankghjh/selfhosted_store/cli/project/projecttest"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConvertOptions(t *testing.T) {
+	a := projecttest.NewApp(projecttest.NewService("test", "foo/test:1.0", map[string]string{"TZ": "Europe/Berlin", "FIXED": "1", "PASSWORD": "changeme"}))
+	a.Parameters = append(a.Parameters,
+		&project.Parameter{Type: project.ParameterTypeVariable, Target: "TZ", Default: "UTC"},
+		&project.Parameter{Type: project.ParameterTypeSecret, Target: "PASSWORD", Required: true, Default: "hunter2"},
+	)
+
+	cfg := Convert(a, defaultArch)
+
+	options := yaml.MapSlice{{Key: "PASSWORD", Value: ""}, {Key: "TZ", Value: "UTC"}}
+	schema := yaml.MapSlice{{Key: "PASSWORD", Value: "password"}, {Key: "TZ", Value: "str?"}}
+	for i, item := range options {
+		if i >= len(cfg.Options) || cfg.Options[i] != item {
+			t.Errorf("options = %v, want %v", cfg.Options, options)
+			break
+		}
+	}
+	for i, item := range schema {
+		if i >= len(cfg.Schema) || cfg.Schema[i] != item {
+			t.Errorf("schema = %v, want %v", cfg.Schema, schema)
+			break
+		}
+	}
+
+	if len(cfg.Environment) != 1 || cfg.Environment["FIXED"] != "1" {
+		t.Errorf("environment = %v, want only FIXED", cfg.Environment)
+	}
+	if cfg.Image != "foo/test" || cfg.Version != "1.0" {
+		t.Errorf("image = %s:%s, want foo/test:1.0", cfg.Image, cfg.Version)
+	}
+
+	res, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"changeme", "hunter2"} {
+		if strings.Contains(string(res), secret) {
+			t.Errorf("config contains the secret %s:\n%s", secret, res)
+		}
+	}
+	if !strings.Contains(Docs(a, cfg), "/data/options.json") {
+		t.Errorf("docs do not explain the options")
+	}
+}
+
+func TestSupported(t *testing.T) {
+	a := projecttest.NewApp(projecttest.NewService("test", "foo/test:1.0", map[string]string{"PASSWORD": ""}))
+	a.Parameters = append(a.Parameters, &project.Parameter{
+		Type: project.ParameterTypeSecret, Target: "PASSWORD", Required: true,
+	})
+	if !Supported(a) {
+		t.Errorf("app with a required variable is not supported")
+	}
+
+	a.Services = append(a.Services, &types.ServiceConfig{Name: "db", Image: "postgres"})
+	if Supported(a) {
+		t.Errorf("multi service app is supported")
+	}
+}
@@ -0,0 +1,436 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yankghjh/selfhosted_store/cli/modules/kubernetes"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("helm", Generater)
+}
+
+// Chart is the Chart.yaml of helm chart
+type Chart struct {
+	APIVersion  string   `yaml:"apiVersion"`
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Type        string   `yaml:"type"`
+	Version     string   `yaml:"version"`
+	AppVersion  string   `yaml:"appVersion,omitempty"`
+	Icon        string   `yaml:"icon,omitempty"`
+	Home        string   `yaml:"home,omitempty"`
+	Keywords    []string `yaml:"keywords,omitempty"`
+}
+
+// Values is the values.yaml of helm chart
+type Values struct {
+	Services    map[string]*ServiceValues `yaml:"services"`
+	Persistence PersistenceValues         `yaml:"persistence"`
+	Ingress     IngressValues             `yaml:"ingress"`
+}
+
+// ServiceValues of a compose service
+type ServiceValues struct {
+	Image        ImageValues       `yaml:"image"`
+	Command      []string          `yaml:"command,omitempty"`
+	Args         []string          `yaml:"args,omitempty"`
+	HostNetwork  bool              `yaml:"hostNetwork"`
+	Privileged   bool              `yaml:"privileged"`
+	Capabilities []string          `yaml:"capabilities,omitempty"`
+	Ports        []PortValues      `yaml:"ports"`
+	Expose       []PortValues      `yaml:"expose,omitempty"`
+	Env          map[string]string `yaml:"env"`
+	SecretEnv    map[string]string `yaml:"secretEnv"`
+	Service      struct {
+		Type string `yaml:"type"`
+	} `yaml:"service"`
+	Persistence []VolumeValues `yaml:"persistence"`
+}
+
+// ImageValues of service
+type ImageValues struct {
+	Repository string `yaml:"repository"`
+	Tag        string `yaml:"tag"`
+	Digest     string `yaml:"digest,omitempty"`
+	PullPolicy string `yaml:"pullPolicy"`
+}
+
+// PortValues of service
+type PortValues struct {
+	Name          string `yaml:"name"`
+	ContainerPort uint32 `yaml:"containerPort"`
+	Port          uint32 `yaml:"port"`
+	Protocol      string `yaml:"protocol"`
+}
+
+// VolumeValues of service
+type VolumeValues struct {
+	Name       string `yaml:"name"`
+	MountPath  string `yaml:"mountPath"`
+	ReadOnly   bool   `yaml:"readOnly"`
+	AccessMode string `yaml:"accessMode"`
+	Size       string `yaml:"size"`
+}
+
+// PersistenceValues of chart
+type PersistenceValues struct {
+	Enabled      bool   `yaml:"enabled"`
+	StorageClass string `yaml:"storageClass"`
+}
+
+// IngressValues of chart, routes to the web ui
+type IngressValues struct {
+	Enabled     bool              `yaml:"enabled"`
+	ClassName   string            `yaml:"className"`
+	Annotations map[string]string `yaml:"annotations"`
+	Host        string            `yaml:"host"`
+	Path        string            `yaml:"path"`
+	Service     string            `yaml:"service"`
+	Port        uint32            `yaml:"port"`
+	TLS         []interface{}     `yaml:"tls"`
+}
+
+// Index is the index.yaml of chart repository
+type Index struct {
+	APIVersion string                   `yaml:"apiVersion"`
+	Entries    map[string][]*IndexEntry `yaml:"entries"`
+	Generated  string                   `yaml:"generated"`
+}
+
+// IndexEntry of a chart version
+type IndexEntry struct {
+	Chart   `yaml:",inline"`
+	URLs    []string `yaml:"urls"`
+	Created string   `yaml:"created"`
+	Digest  string   `yaml:"digest"`
+}
+
+// Generater helm charts and the chart repository index
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	version := o.Config.GetString("chart_version")
+	if version == "" {
+		version = "0.1.0"
+	}
+	storageSize := o.Config.GetString("storage_size")
+	if storageSize == "" {
+		storageSize = "1Gi"
+	}
+	baseURL := o.Config.GetString("url")
+	if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	path := o.Project.GetDistPath("helm")
+	os.MkdirAll(path, os.ModePerm)
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	index := &Index{
+		APIVersion: "v1",
+		Entries:    map[string][]*IndexEntry{},
+		Generated:  now,
+	}
+
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		chart := NewChart(a, version)
+		files, err := ChartFiles(a, chart, storageSize)
+		if err != nil {
+			return fmt.Errorf("create chart %s error: %s", chart.Name, err.Error())
+		}
+
+		for name, content := range files {
+			filename := path + "/charts/" + chart.Name + "/" + name
+			os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+			err := ioutil.WriteFile(filename, content, os.ModePerm)
+			if err != nil {
+				return fmt.Errorf("write chart file [%s] error: %s", filename, err.Error())
+			}
+		}
+
+		modTime := ModTime(a)
+		archive, err := Package(chart.Name, files, modTime)
+		if err != nil {
+			return fmt.Errorf("package chart %s error: %s", chart.Name, err.Error())
+		}
+
+		archiveName := chart.Name + "-" + chart.Version + ".tgz"
+		filename := path + "/" + archiveName
+		err = ioutil.WriteFile(filename, archive, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write chart archive [%s] error: %s", filename, err.Error())
+		}
+
+		digest := sha256.Sum256(archive)
+		index.Entries[chart.Name] = append(index.Entries[chart.Name], &IndexEntry{
+			Chart:   *chart,
+			URLs:    []string{baseURL + archiveName},
+			Created: created(a, now),
+			Digest:  hex.EncodeToString(digest[:]),
+		})
+	}
+
+	res, err := yaml.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("marshal chart index error: %s", err.Error())
+	}
+
+	filename := path + "/index.yaml"
+	err = ioutil.WriteFile(filename, res, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("write chart index [%s] error: %s", filename, err.Error())
+	}
+
+	return nil
+}
+
+// NewChart from application
+func NewChart(a *project.Application, version string) *Chart {
+	chart := &Chart{
+		APIVersion:  "v2",
+		Name:        a.GetID(),
+		Description: a.Description,
+		Type:        "application",
+		Version:     version,
+		Icon:        a.Icon,
+		Keywords:    a.Category,
+	}
+
+	if image := project.ParseImage(a.Services[0].Image); image.Tag != "" {
+		chart.AppVersion = image.Tag
+	}
+
+	if l := a.GetLink(project.LinkTypeProject); l != nil {
+		chart.Home = l.URL
+	}
+
+	return chart
+}
+
+// NewValues from application
+func NewValues(a *project.Application, storageSize string) *Values {
+	values := &Values{
+		Services:    map[string]*ServiceValues{},
+		Persistence: PersistenceValues{Enabled: true},
+		Ingress: IngressValues{
+			Host:        a.GetID() + ".local",
+			Path:        "/",
+			Annotations: map[string]string{},
+			TLS:         []interface{}{},
+		},
+	}
+
+	for _, service := range a.Services {
+		name := kubernetes.ComponentName(a, service)
+		image := project.ParseImage(service.Image)
+
+		s := &ServiceValues{
+			Image: ImageValues{
+				Repository: image.Name(),
+				Tag:        image.Tag,
+				Digest:     image.Digest,
+				PullPolicy: "IfNotPresent",
+			},
+			Command:      service.Entrypoint,
+			Args:         service.Command,
+			HostNetwork:  service.NetworkMode == "host",
+			Privileged:   service.Privileged,
+			Capabilities: service.CapAdd,
+			Ports:        []PortValues{},
+			Persistence:  []VolumeValues{},
+		}
+		s.Service.Type = "ClusterIP"
+		s.Env, s.SecretEnv = kubernetes.SplitEnvironment(a, service)
+
+		// a target port published twice is suffixed with the published port
+		names := map[string]bool{}
+		for _, port := range service.Ports {
+			protocol := strings.ToUpper(port.Protocol)
+			if protocol == "" {
+				protocol = "TCP"
+			}
+			published := port.Published
+			if published == 0 {
+				published = port.Target
+			}
+			portName := strings.ToLower(protocol) + "-" + strconv.Itoa(int(port.Target))
+			if names[portName] {
+				portName += "-" + strconv.Itoa(int(published))
+			}
+			names[portName] = true
+			s.Ports = append(s.Ports, PortValues{
+				Name:          portName,
+				ContainerPort: port.Target,
+				Port:          published,
+				Protocol:      protocol,
+			})
+
+			// ingress routes to the web ui, or the first port
+			if values.Ingress.Service == "" || (a.WebUI != nil && a.WebUI.Port == port.Target) {
+				values.Ingress.Service = name
+				values.Ingress.Port = published
+			}
+		}
+
+		// a headless service on the exposed ports when nothing is published
+		if len(s.Ports) == 0 {
+			for _, port := range kubernetes.ExposedPorts(service) {
+				s.Expose = append(s.Expose, PortValues{
+					Name:          port.Name,
+					ContainerPort: port.TargetPort,
+					Port:          port.Port,
+					Protocol:      port.Protocol,
+				})
+			}
+		}
+
+		for _, volume := range service.Volumes {
+			if volume.Type == "tmpfs" {
+				continue
+			}
+			s.Persistence = append(s.Persistence, VolumeValues{
+				Name:       project.Slugify(volume.Target),
+				MountPath:  volume.Target,
+				ReadOnly:   volume.ReadOnly,
+				AccessMode: "ReadWriteOnce",
+				Size:       storageSize,
+			})
+		}
+
+		values.Services[name] = s
+	}
+
+	return values
+}
+
+// ChartFiles of application, file name to content,
+// the digest of the chart content is appended to the chart version as build metadata
+func ChartFiles(a *project.Application, chart *Chart, storageSize string) (map[string][]byte, error) {
+	chartYAML, err := yaml.Marshal(chart)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesYAML, err := yaml.Marshal(NewValues(a, storageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{
+		"Chart.yaml":              chartYAML,
+		"values.yaml":             valuesYAML,
+		"templates/_helpers.tpl":  []byte(helpersTemplate),
+		"templates/workload.yaml": []byte(workloadTemplate),
+		"templates/config.yaml":   []byte(configTemplate),
+		"templates/service.yaml":  []byte(serviceTemplate),
+		"templates/pvc.yaml":      []byte(pvcTemplate),
+		"templates/ingress.yaml":  []byte(ingressTemplate),
+	}
+
+	chart.Version = ContentVersion(chart.Version, files)
+	files["Chart.yaml"], err = yaml.Marshal(chart)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// ContentVersion of chart, version with the short digest of files,
+// clients refresh cached charts when the content changes
+func ContentVersion(version string, files map[string][]byte) string {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(files[name])
+		h.Write([]byte{0})
+	}
+
+	return strings.SplitN(version, "+", 2)[0] + "+" + hex.EncodeToString(h.Sum(nil))[:8]
+}
+
+// ModTime of chart files, the last update of app or the unix epoch,
+// which keeps the archive and its digest stable between builds
+func ModTime(a *project.Application) time.Time {
+	if a.LastUpdate.IsZero() {
+		return time.Unix(0, 0).UTC()
+	}
+
+	return a.LastUpdate.UTC()
+}
+
+// created time of chart in index, the last update of app or the build time
+func created(a *project.Application, now string) string {
+	if a.LastUpdate.IsZero() {
+		return now
+	}
+
+	return a.LastUpdate.UTC().Format(time.RFC3339)
+}
+
+// Package chart files to tgz archive
+func Package(name string, files map[string][]byte, modTime time.Time) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	names := []string{}
+	for filename := range files {
+		names = append(names, filename)
+	}
+	sort.Strings(names)
+
+	for _, filename := range names {
+		content := files[filename]
+		err := tw.WriteHeader(&tar.Header{
+			Name:    name + "/" + filename,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: modTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
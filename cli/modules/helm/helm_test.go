@@ -0,0 +1,142 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"github.com/yankghjh/selfhosted_store/cli/project/projecttest"
+)
+
+func TestPackage(t *testing.T) {
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	files := map[string][]byte{
+		"Chart.yaml":  []byte("name: test\n"),
+		"values.yaml": []byte("image: foo/test\n"),
+	}
+
+	first, err := Package("test", files, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Package("test", files, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("archives of the same files differ")
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			break
+		}
+		if !header.ModTime.Equal(modTime) {
+			t.Errorf("%s: mod time = %s, want %s", header.Name, header.ModTime, modTime)
+		}
+	}
+}
+
+func TestModTime(t *testing.T) {
+	updated := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		update time.Time
+		want   time.Time
+	}{
+		{time.Time{}, time.Unix(0, 0).UTC()},
+		{updated, updated},
+	}
+	for _, tt := range tests {
+		a := project.NewApplication()
+		a.LastUpdate = tt.update
+		if got := ModTime(a); !got.Equal(tt.want) {
+			t.Errorf("ModTime() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestCreated(t *testing.T) {
+	a := project.NewApplication()
+	if got := created(a, "2021-01-01T00:00:00Z"); got != "2021-01-01T00:00:00Z" {
+		t.Errorf("created of app without last update = %s, want the build time", got)
+	}
+	a.LastUpdate = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := created(a, "2021-01-01T00:00:00Z"); got != "2020-01-02T03:04:05Z" {
+		t.Errorf("created = %s, want the last update", got)
+	}
+}
+
+func TestChartFilesVersion(t *testing.T) {
+	versions := []string{}
+	for _, image := range []string{"foo/test:1.0", "foo/test:1.0", "foo/test:1.1"} {
+		a := projecttest.NewApp(projecttest.NewService("test", image, nil))
+		chart := NewChart(a, "0.1.0")
+		files, err := ChartFiles(a, chart, "1Gi")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(chart.Version, "0.1.0+") {
+			t.Errorf("version = %s, want 0.1.0+digest", chart.Version)
+		}
+		if !strings.Contains(string(files["Chart.yaml"]), "version: "+chart.Version) {
+			t.Errorf("Chart.yaml has no version %s:\n%s", chart.Version, files["Chart.yaml"])
+		}
+		versions = append(versions, chart.Version)
+	}
+
+	if versions[0] != versions[1] {
+		t.Errorf("versions of the same chart differ: %v", versions)
+	}
+	if versions[0] == versions[2] {
+		t.Errorf("versions of changed charts are the same: %v", versions)
+	}
+}
+
+func TestValuesExpose(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:   "db",
+		Image:  "postgres",
+		Expose: types.StringOrNumberList{"5432"},
+	})
+
+	values := NewValues(a, "1Gi")
+	db := values.Services["db"]
+	if len(db.Ports) != 0 || len(db.Expose) != 1 || db.Expose[0].Port != 5432 {
+		t.Errorf("db ports = %+v, expose = %+v, want expose 5432", db.Ports, db.Expose)
+	}
+	if !strings.Contains(serviceTemplate, "clusterIP: None") {
+		t.Errorf("service template has no headless service")
+	}
+}
+
+func TestValuesPortNames(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:  "web",
+		Image: "nginx",
+		Ports: []types.ServicePortConfig{{Target: 80, Published: 8080}, {Target: 80, Published: 8081}},
+	})
+
+	values := NewValues(a, "1Gi")
+	names := []string{}
+	for _, port := range values.Services["web"].Ports {
+		names = append(names, port.Name)
+	}
+	if strings.Join(names, ",") != "tcp-80,tcp-80-8081" {
+		t.Errorf("port names = %v, want [tcp-80 tcp-80-8081]", names)
+	}
+}
@@ -0,0 +1,229 @@
+package helm
+
+// chart templates are the same for all apps, apps differ in values.yaml
+
+var helpersTemplate = `{{- define "app.fullname" -}}
+{{- if contains .Chart.Name .Release.Name -}}
+{{- .Release.Name | trunc 63 | trimSuffix "-" -}}
+{{- else -}}
+{{- printf "%s-%s" .Release.Name .Chart.Name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "app.selectorLabels" -}}
+app.kubernetes.io/name: {{ .Chart.Name }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end -}}
+
+{{- define "app.labels" -}}
+helm.sh/chart: {{ printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" }}
+{{ include "app.selectorLabels" . }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end -}}
+`
+
+var workloadTemplate = `{{- range $name, $svc := .Values.services }}
+---
+apiVersion: apps/v1
+kind: {{ if $svc.persistence }}StatefulSet{{ else }}Deployment{{ end }}
+metadata:
+  name: {{ include "app.fullname" $ }}-{{ $name }}
+  labels:
+    {{- include "app.labels" $ | nindent 4 }}
+    app.kubernetes.io/component: {{ $name }}
+spec:
+  {{- if $svc.persistence }}
+  serviceName: {{ include "app.fullname" $ }}-{{ $name }}
+  {{- end }}
+  replicas: 1
+  selector:
+    matchLabels:
+      {{- include "app.selectorLabels" $ | nindent 6 }}
+      app.kubernetes.io/component: {{ $name }}
+  template:
+    metadata:
+      labels:
+        {{- include "app.selectorLabels" $ | nindent 8 }}
+        app.kubernetes.io/component: {{ $name }}
+    spec:
+      {{- if $svc.hostNetwork }}
+      hostNetwork: true
+      {{- end }}
+      containers:
+        - name: {{ $name }}
+          image: "{{ $svc.image.repository }}{{ with $svc.image.tag }}:{{ . }}{{ end }}{{ with $svc.image.digest }}@{{ . }}{{ end }}"
+          imagePullPolicy: {{ $svc.image.pullPolicy }}
+          {{- with $svc.command }}
+          command:
+            {{- toYaml . | nindent 12 }}
+          {{- end }}
+          {{- with $svc.args }}
+          args:
+            {{- toYaml . | nindent 12 }}
+          {{- end }}
+          {{- if or $svc.privileged $svc.capabilities }}
+          securityContext:
+            privileged: {{ $svc.privileged }}
+            {{- with $svc.capabilities }}
+            capabilities:
+              add:
+                {{- toYaml . | nindent 16 }}
+            {{- end }}
+          {{- end }}
+          {{- with $svc.ports }}
+          ports:
+            {{- range . }}
+            - name: {{ .name }}
+              containerPort: {{ .containerPort }}
+              protocol: {{ .protocol }}
+            {{- end }}
+          {{- end }}
+          {{- if or $svc.env $svc.secretEnv }}
+          envFrom:
+            {{- if $svc.env }}
+            - configMapRef:
+                name: {{ include "app.fullname" $ }}-{{ $name }}-env
+            {{- end }}
+            {{- if $svc.secretEnv }}
+            - secretRef:
+                name: {{ include "app.fullname" $ }}-{{ $name }}-secret
+            {{- end }}
+          {{- end }}
+          {{- with $svc.persistence }}
+          volumeMounts:
+            {{- range . }}
+            - name: {{ .name }}
+              mountPath: {{ .mountPath }}
+              readOnly: {{ .readOnly }}
+            {{- end }}
+          {{- end }}
+      {{- with $svc.persistence }}
+      volumes:
+        {{- range . }}
+        - name: {{ .name }}
+          {{- if $.Values.persistence.enabled }}
+          persistentVolumeClaim:
+            claimName: {{ include "app.fullname" $ }}-{{ $name }}-{{ .name }}
+          {{- else }}
+          emptyDir: {}
+          {{- end }}
+        {{- end }}
+      {{- end }}
+{{- end }}
+`
+
+var configTemplate = `{{- range $name, $svc := .Values.services }}
+{{- with $svc.env }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "app.fullname" $ }}-{{ $name }}-env
+  labels:
+    {{- include "app.labels" $ | nindent 4 }}
+data:
+  {{- range $key, $value := . }}
+  {{ $key }}: {{ $value | quote }}
+  {{- end }}
+{{- end }}
+{{- with $svc.secretEnv }}
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ include "app.fullname" $ }}-{{ $name }}-secret
+  labels:
+    {{- include "app.labels" $ | nindent 4 }}
+type: Opaque
+stringData:
+  {{- range $key, $value := . }}
+  {{ $key }}: {{ $value | quote }}
+  {{- end }}
+{{- end }}
+{{- end }}
+`
+
+var serviceTemplate = `{{- range $name, $svc := .Values.services }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "app.fullname" $ }}-{{ $name }}
+  labels:
+    {{- include "app.labels" $ | nindent 4 }}
+spec:
+  {{- if $svc.ports }}
+  type: {{ $svc.service.type }}
+  {{- else }}
+  clusterIP: None
+  {{- end }}
+  selector:
+    {{- include "app.selectorLabels" $ | nindent 4 }}
+    app.kubernetes.io/component: {{ $name }}
+  ports:
+    {{- range (default $svc.expose $svc.ports) }}
+    - name: {{ .name }}
+      port: {{ .port }}
+      targetPort: {{ .containerPort }}
+      protocol: {{ .protocol }}
+    {{- else }} []
+    {{- end }}
+{{- end }}
+`
+
+var pvcTemplate = `{{- if .Values.persistence.enabled }}
+{{- range $name, $svc := .Values.services }}
+{{- range $svc.persistence }}
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: {{ include "app.fullname" $ }}-{{ $name }}-{{ .name }}
+  labels:
+    {{- include "app.labels" $ | nindent 4 }}
+spec:
+  accessModes:
+    - {{ .accessMode }}
+  {{- with $.Values.persistence.storageClass }}
+  storageClassName: {{ . }}
+  {{- end }}
+  resources:
+    requests:
+      storage: {{ .size }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
+
+var ingressTemplate = `{{- if .Values.ingress.enabled }}
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{ include "app.fullname" . }}
+  labels:
+    {{- include "app.labels" . | nindent 4 }}
+  {{- with .Values.ingress.annotations }}
+  annotations:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+spec:
+  {{- with .Values.ingress.className }}
+  ingressClassName: {{ . }}
+  {{- end }}
+  {{- with .Values.ingress.tls }}
+  tls:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+  rules:
+    - host: {{ .Values.ingress.host | quote }}
+      http:
+        paths:
+          - path: {{ .Values.ingress.path }}
+            pathType: Prefix
+            backend:
+              service:
+                name: {{ include "app.fullname" . }}-{{ .Values.ingress.service }}
+                port:
+                  number: {{ .Values.ingress.port }}
+{{- end }}
+`
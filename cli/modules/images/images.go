@@ -0,0 +1,206 @@
+package images
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("images", Generater)
+}
+
+// Registry with its referenced images, sorted by repository
+type Registry struct {
+	Name   string
+	Images []*project.Image
+}
+
+// SkopeoRegistry of skopeo sync yaml, repository to tags and digests
+type SkopeoRegistry struct {
+	Images map[string][]string `yaml:"images"`
+}
+
+// RegSync config of regsync
+type RegSync struct {
+	Version  int            `yaml:"version"`
+	Defaults RegSyncDefault `yaml:"defaults"`
+	Sync     []*RegSyncItem `yaml:"sync"`
+}
+
+// RegSyncDefault of regsync
+type RegSyncDefault struct {
+	Parallel int `yaml:"parallel"`
+}
+
+// RegSyncItem of regsync, an image to copy
+type RegSyncItem struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target"`
+	Type   string `yaml:"type"`
+}
+
+// Generater image mirror list and sync configs, images are grouped by source registry
+//
+//	mirror: registry.example.com
+func Generater(o *project.Operator) error {
+	o.Config.SetDefault("mirror", "registry.example.com")
+	mirror := strings.TrimSuffix(o.Config.GetString("mirror"), "/")
+
+	registries := Collect(o.Project.Apps)
+
+	list := []string{}
+	for _, r := range registries {
+		for _, image := range r.Images {
+			list = append(list, image.String())
+		}
+	}
+
+	skopeo, err := yaml.Marshal(Skopeo(registries))
+	if err != nil {
+		return fmt.Errorf("marshal skopeo sync config error: %s", err.Error())
+	}
+	regsync, err := yaml.Marshal(NewRegSync(registries, mirror))
+	if err != nil {
+		return fmt.Errorf("marshal regsync config error: %s", err.Error())
+	}
+
+	path := o.Project.GetDistPath("images")
+	os.MkdirAll(path, os.ModePerm)
+
+	for name, content := range map[string]string{
+		"images.txt":  strings.Join(list, "\n") + "\n",
+		"skopeo.yml":  string(skopeo),
+		"regsync.yml": string(regsync),
+		"crane.sh":    Crane(registries, mirror),
+	} {
+		filename := path + "/" + name
+		err := ioutil.WriteFile(filename, []byte(content), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write images file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Collect normalized images of apps and their variants, de-duplicated and grouped by registry
+func Collect(apps []*project.Application) []*Registry {
+	seen := map[string]bool{}
+	index := map[string]*Registry{}
+	registries := []*Registry{}
+
+	var collect func(apps []*project.Application)
+	collect = func(apps []*project.Application) {
+		for _, a := range apps {
+			for _, service := range a.Services {
+				if service.Image == "" {
+					continue
+				}
+				image := project.ParseImage(service.Image)
+				if seen[image.String()] {
+					continue
+				}
+				seen[image.String()] = true
+
+				r, ok := index[image.Registry]
+				if !ok {
+					r = &Registry{Name: image.Registry, Images: []*project.Image{}}
+					index[image.Registry] = r
+					registries = append(registries, r)
+				}
+				r.Images = append(r.Images, image)
+			}
+			collect(a.Variants)
+		}
+	}
+	collect(apps)
+
+	sort.Slice(registries, func(i, j int) bool {
+		return registries[i].Name < registries[j].Name
+	})
+	for _, r := range registries {
+		images := r.Images
+		sort.Slice(images, func(i, j int) bool {
+			return images[i].String() < images[j].String()
+		})
+	}
+
+	return registries
+}
+
+// Skopeo sync yaml, the digest is used when the image is pinned
+func Skopeo(registries []*Registry) map[string]*SkopeoRegistry {
+	cfg := map[string]*SkopeoRegistry{}
+
+	for _, r := range registries {
+		s := &SkopeoRegistry{Images: map[string][]string{}}
+		for _, image := range r.Images {
+			s.Images[image.Repository] = append(s.Images[image.Repository], version(image))
+		}
+		cfg[r.Name] = s
+	}
+
+	return cfg
+}
+
+// NewRegSync config copying images to the mirror
+func NewRegSync(registries []*Registry, mirror string) *RegSync {
+	cfg := &RegSync{
+		Version:  1,
+		Defaults: RegSyncDefault{Parallel: 2},
+		Sync:     []*RegSyncItem{},
+	}
+
+	for _, r := range registries {
+		for _, image := range r.Images {
+			cfg.Sync = append(cfg.Sync, &RegSyncItem{
+				Source: image.String(),
+				Target: target(image, mirror),
+				Type:   "image",
+			})
+		}
+	}
+
+	return cfg
+}
+
+// Crane script copying images to the mirror
+func Crane(registries []*Registry, mirror string) string {
+	b := &strings.Builder{}
+	b.WriteString("#!/bin/sh\nset -e\n")
+
+	for _, r := range registries {
+		b.WriteString("\n# " + r.Name + "\n")
+		for _, image := range r.Images {
+			b.WriteString("crane copy " + image.String() + " " + target(image, mirror) + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// target reference of image in mirror, prefixed by the source registry
+// to keep the same repository of different registries apart
+func target(image *project.Image, mirror string) string {
+	name := mirror + "/" + image.Registry + "/" + image.Repository
+	if image.Digest != "" && image.Tag == "" {
+		return name + "@" + image.Digest
+	}
+
+	return name + ":" + image.Tag
+}
+
+// version of image, digest or tag
+func version(image *project.Image) string {
+	if image.Digest != "" {
+		return image.Digest
+	}
+
+	return image.Tag
+}
@@ -0,0 +1,48 @@
+package images
+
+import (
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"github.com/yankghjh/selfhosted_store/cli/project/projecttest"
+)
+
+func TestCollect(t *testing.T) {
+	registries := Collect([]*project.Application{
+		projecttest.NewApp(projecttest.NewService("db", "postgres:13", nil), projecttest.NewService("bar", "ghcr.io/foo/bar", nil)),
+		projecttest.NewApp(projecttest.NewService("db", "docker.io/library/postgres:13", nil), projecttest.NewService("bar", "foo/bar", nil)),
+	})
+
+	want := map[string][]string{
+		"docker.io": {"docker.io/foo/bar:latest", "docker.io/library/postgres:13"},
+		"ghcr.io":   {"ghcr.io/foo/bar:latest"},
+	}
+	if len(registries) != len(want) {
+		t.Fatalf("registries = %d, want %d", len(registries), len(want))
+	}
+	for _, r := range registries {
+		if len(r.Images) != len(want[r.Name]) {
+			t.Errorf("%s: images = %v, want %v", r.Name, r.Images, want[r.Name])
+			continue
+		}
+		for i, image := range r.Images {
+			if image.String() != want[r.Name][i] {
+				t.Errorf("%s: image %d = %s, want %s", r.Name, i, image, want[r.Name][i])
+			}
+		}
+	}
+}
+
+func TestTarget(t *testing.T) {
+	cases := map[string]string{
+		"foo/bar":                  "mirror.local/docker.io/foo/bar:latest",
+		"ghcr.io/foo/bar":          "mirror.local/ghcr.io/foo/bar:latest",
+		"ghcr.io/foo/bar@sha256:1": "mirror.local/ghcr.io/foo/bar@sha256:1",
+	}
+
+	for ref, want := range cases {
+		if got := target(project.ParseImage(ref), "mirror.local"); got != want {
+			t.Errorf("target(%s) = %s, want %s", ref, got, want)
+		}
+	}
+}
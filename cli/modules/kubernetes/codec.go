@@ -0,0 +1,111 @@
+package kubernetes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/viper"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterEncoder("kubernetes", Encoder)
+	project.RegisterGenerater("kubernetes", Generater)
+}
+
+// Encoder for kubernetes manifests in one multi document yaml
+func Encoder(a *project.Application) ([]byte, error) {
+	manifests, err := Convert(a, DefaultOption())
+	if err != nil {
+		return nil, err
+	}
+
+	out := []byte{}
+	for i, m := range manifests {
+		res, err := yaml.Marshal(m.Resource)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			out = append(out, []byte("---\n")...)
+		}
+		out = append(out, res...)
+	}
+
+	return out, nil
+}
+
+// Generater kubernetes manifests, one directory with kustomization per app
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	opt := LoadOption(o.Config)
+
+	for _, a := range apps {
+		manifests, err := Convert(a, opt)
+		if err != nil {
+			return fmt.Errorf("convert application %s to kubernetes manifests error: %s", a.Name, err.Error())
+		}
+
+		path := o.Project.GetDistPath("kubernetes", a.GetID())
+		os.MkdirAll(path, os.ModePerm)
+
+		kustomization := &Kustomization{
+			APIVersion: "kustomize.config.k8s.io/v1beta1",
+			Kind:       "Kustomization",
+			Namespace:  opt.Namespace,
+			Resources:  []string{},
+		}
+
+		for _, m := range manifests {
+			kustomization.Resources = append(kustomization.Resources, m.Filename)
+			if err := writeYAML(path+"/"+m.Filename, m.Resource); err != nil {
+				return err
+			}
+		}
+
+		if err := writeYAML(path+"/kustomization.yaml", kustomization); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadOption from generater config
+func LoadOption(cfg *viper.Viper) Option {
+	opt := DefaultOption()
+	opt.Namespace = cfg.GetString("namespace")
+	opt.ServiceType = cfg.GetString("service_type")
+	opt.StorageClass = cfg.GetString("storage_class")
+	if v := cfg.GetString("volume_mode"); v != "" {
+		opt.VolumeMode = v
+	}
+	if v := cfg.GetString("storage_size"); v != "" {
+		opt.StorageSize = v
+	}
+	if v := cfg.GetString("data_path"); v != "" {
+		opt.DataPath = v
+	}
+
+	return opt
+}
+
+func writeYAML(filename string, v interface{}) error {
+	res, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %s error: %s", filename, err.Error())
+	}
+
+	err = ioutil.WriteFile(filename, res, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("write file [%s] error: %s", filename, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,413 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// Volume modes
+const (
+	VolumeModePVC      = "pvc"
+	VolumeModeHostPath = "hostpath"
+)
+
+// Option for kubernetes manifests
+type Option struct {
+	Namespace    string
+	ServiceType  string
+	VolumeMode   string
+	StorageClass string
+	StorageSize  string
+	// DataPath is the root of host path volumes
+	DataPath string
+}
+
+// Manifest is a resource with its file name
+type Manifest struct {
+	Filename string
+	Resource interface{}
+}
+
+// DefaultOption of kubernetes manifests
+func DefaultOption() Option {
+	return Option{
+		VolumeMode:  VolumeModePVC,
+		StorageSize: "1Gi",
+		DataPath:    "/opt/appdata",
+	}
+}
+
+// Convert application to kubernetes manifests
+func Convert(a *project.Application, opt Option) ([]*Manifest, error) {
+	if len(a.Services) == 0 {
+		return nil, fmt.Errorf("no service found")
+	}
+	if opt.VolumeMode != VolumeModePVC && opt.VolumeMode != VolumeModeHostPath {
+		return nil, fmt.Errorf("unknown volume mode [%s]", opt.VolumeMode)
+	}
+
+	c := &converter{
+		app:       a,
+		opt:       opt,
+		manifests: []*Manifest{},
+		claims:    map[string]bool{},
+	}
+
+	for _, service := range a.Services {
+		c.convertService(service)
+	}
+
+	return c.manifests, nil
+}
+
+type converter struct {
+	app       *project.Application
+	opt       Option
+	manifests []*Manifest
+	claims    map[string]bool
+}
+
+func (c *converter) add(kind, name string, resource interface{}) {
+	c.manifests = append(c.manifests, &Manifest{
+		Filename: name + "-" + strings.ToLower(kind) + ".yaml",
+		Resource: resource,
+	})
+}
+
+func (c *converter) object(apiVersion, kind, name string, labels map[string]string) Object {
+	return Object{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Metadata: Metadata{
+			Name:      name,
+			Namespace: c.opt.Namespace,
+			Labels:    labels,
+		},
+	}
+}
+
+func (c *converter) convertService(service *types.ServiceConfig) {
+	name := ServiceName(c.app, service)
+	component := ComponentName(c.app, service)
+	labels := map[string]string{
+		"app.kubernetes.io/name":      c.app.GetID(),
+		"app.kubernetes.io/component": component,
+	}
+
+	container := Container{
+		Name:       component,
+		Image:      service.Image,
+		Command:    service.Entrypoint,
+		Args:       service.Command,
+		WorkingDir: service.WorkingDir,
+	}
+
+	if service.Privileged || len(service.CapAdd) > 0 || len(service.CapDrop) > 0 {
+		container.SecurityContext = &SecurityContext{Privileged: service.Privileged}
+		if len(service.CapAdd) > 0 || len(service.CapDrop) > 0 {
+			container.SecurityContext.Capabilities = &Capabilities{
+				Add:  service.CapAdd,
+				Drop: service.CapDrop,
+			}
+		}
+	}
+
+	pod := PodSpec{
+		HostNetwork: service.NetworkMode == "host",
+		Hostname:    service.Hostname,
+	}
+
+	// ports
+	// a target port published twice is declared once in container,
+	// the service port names are suffixed with the published port
+	servicePorts := []ServicePort{}
+	declared := map[string]bool{}
+	for _, port := range service.Ports {
+		protocol := strings.ToUpper(port.Protocol)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		portName := strings.ToLower(protocol) + "-" + strconv.Itoa(int(port.Target))
+		if port.Published != 0 {
+			name := portName
+			if declared[portName] {
+				name += "-" + strconv.Itoa(int(port.Published))
+			}
+			servicePorts = append(servicePorts, ServicePort{
+				Name:       name,
+				Port:       port.Published,
+				TargetPort: port.Target,
+				Protocol:   protocol,
+			})
+		}
+
+		if declared[portName] {
+			continue
+		}
+		declared[portName] = true
+		container.Ports = append(container.Ports, ContainerPort{
+			Name:          portName,
+			ContainerPort: port.Target,
+			Protocol:      protocol,
+		})
+	}
+
+	// environment
+	data, secrets := SplitEnvironment(c.app, service)
+	c.renameHosts(data)
+	c.renameHosts(secrets)
+	if len(data) > 0 {
+		configMap := &ConfigMap{
+			Object: c.object("v1", "ConfigMap", name+"-env", labels),
+			Data:   data,
+		}
+		c.add("ConfigMap", configMap.Metadata.Name, configMap)
+		container.EnvFrom = append(container.EnvFrom, EnvFromSource{
+			ConfigMapRef: &LocalObjectReference{Name: configMap.Metadata.Name},
+		})
+	}
+	if len(secrets) > 0 {
+		secret := &Secret{
+			Object:     c.object("v1", "Secret", name+"-secret", labels),
+			Type:       "Opaque",
+			StringData: secrets,
+		}
+		c.add("Secret", secret.Metadata.Name, secret)
+		container.EnvFrom = append(container.EnvFrom, EnvFromSource{
+			SecretRef: &LocalObjectReference{Name: secret.Metadata.Name},
+		})
+	}
+
+	// volumes
+	for _, volume := range service.Volumes {
+		if volume.Type == "tmpfs" {
+			continue
+		}
+		v := c.convertVolume(name, volume)
+		pod.Volumes = appendVolume(pod.Volumes, v)
+		container.VolumeMounts = append(container.VolumeMounts, VolumeMount{
+			Name:      v.Name,
+			MountPath: volume.Target,
+			ReadOnly:  volume.ReadOnly,
+		})
+	}
+
+	for i, device := range service.Devices {
+		parts := strings.SplitN(device, ":", 3)
+		target := parts[0]
+		if len(parts) > 1 {
+			target = parts[1]
+		}
+		v := Volume{
+			Name:     name + "-device-" + strconv.Itoa(i),
+			HostPath: &HostPathSource{Path: parts[0]},
+		}
+		pod.Volumes = appendVolume(pod.Volumes, v)
+		container.VolumeMounts = append(container.VolumeMounts, VolumeMount{
+			Name:      v.Name,
+			MountPath: target,
+		})
+	}
+
+	pod.Containers = []Container{container}
+
+	kind := "Deployment"
+	if len(service.Volumes) > 0 {
+		kind = "StatefulSet"
+	}
+	workload := &Workload{
+		Object: c.object("apps/v1", kind, name, labels),
+		Spec: WorkloadSpec{
+			Replicas: 1,
+			Selector: Selector{MatchLabels: labels},
+			Template: PodTemplate{
+				Metadata: Metadata{Labels: labels},
+				Spec:     pod,
+			},
+		},
+	}
+	if kind == "StatefulSet" {
+		workload.Spec.ServiceName = name
+	}
+	c.add(kind, name, workload)
+
+	// a headless service on the exposed ports when nothing is published,
+	// which is also the governing service of the stateful set
+	spec := ServiceSpec{
+		Type:     c.opt.ServiceType,
+		Selector: labels,
+		Ports:    servicePorts,
+	}
+	if len(servicePorts) == 0 {
+		spec.Type = ""
+		spec.ClusterIP = "None"
+		spec.Ports = ExposedPorts(service)
+	}
+	c.add("Service", name, &Service{
+		Object: c.object("v1", "Service", name, labels),
+		Spec:   spec,
+	})
+}
+
+// renameHosts in environment, values addressing a compose service like
+// db or db:5432 are pointed to the prefixed service name
+func (c *converter) renameHosts(env map[string]string) {
+	for key, value := range env {
+		for _, s := range c.app.Services {
+			component := ComponentName(c.app, s)
+			if value == component || strings.HasPrefix(value, component+":") {
+				env[key] = ServiceName(c.app, s) + strings.TrimPrefix(value, component)
+				break
+			}
+		}
+	}
+}
+
+// ExposedPorts of service, the target ports and the exposed ports
+func ExposedPorts(service *types.ServiceConfig) []ServicePort {
+	ports := []ServicePort{}
+	seen := map[string]bool{}
+	add := func(port uint32, protocol string) {
+		protocol = strings.ToUpper(protocol)
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		portName := strings.ToLower(protocol) + "-" + strconv.Itoa(int(port))
+		if seen[portName] {
+			return
+		}
+		seen[portName] = true
+		ports = append(ports, ServicePort{
+			Name:       portName,
+			Port:       port,
+			TargetPort: port,
+			Protocol:   protocol,
+		})
+	}
+
+	for _, port := range service.Ports {
+		add(port.Target, port.Protocol)
+	}
+	for _, expose := range service.Expose {
+		parts := strings.SplitN(expose, "/", 2)
+		port, err := strconv.ParseUint(parts[0], 10, 16)
+		if err != nil {
+			continue
+		}
+		protocol := ""
+		if len(parts) == 2 {
+			protocol = parts[1]
+		}
+		add(uint32(port), protocol)
+	}
+
+	return ports
+}
+
+// convertVolume to host path or claim, named volumes are shared by services
+func (c *converter) convertVolume(service string, volume types.ServiceVolumeConfig) Volume {
+	named := volume.Type == "volume" && volume.Source != "" && !strings.HasPrefix(volume.Source, project.DataPathPrefix)
+
+	name := project.Slugify(service + "-" + volume.Target)
+	if named {
+		name = project.Slugify(c.app.GetID() + "-" + volume.Source)
+	}
+
+	if c.opt.VolumeMode == VolumeModeHostPath {
+		root := strings.TrimSuffix(c.opt.DataPath, "/") + "/" + c.app.GetID()
+		path := project.ResolveDataPath(volume.Source, c.opt.DataPath)
+		if named {
+			path = root + "/" + volume.Source
+		} else if volume.Source == "" {
+			path = root + "/" + name
+		}
+
+		return Volume{
+			Name:     name,
+			HostPath: &HostPathSource{Path: path, Type: "DirectoryOrCreate"},
+		}
+	}
+
+	if !c.claims[name] {
+		c.claims[name] = true
+		claim := &PersistentVolumeClaim{
+			Object: c.object("v1", "PersistentVolumeClaim", name, map[string]string{
+				"app.kubernetes.io/name": c.app.GetID(),
+			}),
+			Spec: PersistentVolumeClaimSpec{
+				AccessModes:      []string{"ReadWriteOnce"},
+				StorageClassName: c.opt.StorageClass,
+				Resources: ResourceRequirements{
+					Requests: map[string]string{"storage": c.opt.StorageSize},
+				},
+			},
+		}
+		c.add("PersistentVolumeClaim", name, claim)
+	}
+
+	return Volume{
+		Name:                  name,
+		PersistentVolumeClaim: &PersistentVolumeClaimSource{ClaimName: name},
+	}
+}
+
+func appendVolume(volumes []Volume, v Volume) []Volume {
+	for _, exist := range volumes {
+		if exist.Name == v.Name {
+			return volumes
+		}
+	}
+
+	return append(volumes, v)
+}
+
+// ComponentName of service in app as dns label
+func ComponentName(a *project.Application, service *types.ServiceConfig) string {
+	name := project.Slugify(service.Name)
+	if name == "" {
+		name = a.GetID()
+	}
+
+	return name
+}
+
+// ServiceName of service resources, prefixed by the app id to keep
+// services of different apps apart in a namespace
+func ServiceName(a *project.Application, service *types.ServiceConfig) string {
+	id := a.GetID()
+	name := ComponentName(a, service)
+	if name == id {
+		return id
+	}
+
+	return id + "-" + name
+}
+
+// SplitEnvironment of service to plain data and secrets by secret parameters,
+// environment without value is ignored
+func SplitEnvironment(a *project.Application, service *types.ServiceConfig) (map[string]string, map[string]string) {
+	data := map[string]string{}
+	secrets := map[string]string{}
+
+	for name, value := range service.Environment {
+		if value == nil {
+			continue
+		}
+
+		p := a.GetParameter(project.ParameterTypeSecret, name)
+		if p == nil {
+			p = a.GetParameter(project.ParameterTypeVariable, name)
+		}
+		if p != nil && p.IsSecret() {
+			secrets[name] = *value
+		} else {
+			data[name] = *value
+		}
+	}
+
+	return data, secrets
+}
@@ -0,0 +1,183 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestConvertServices(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services,
+		&types.ServiceConfig{
+			Name:    "db",
+			Image:   "postgres:13",
+			Expose:  types.StringOrNumberList{"5432"},
+			Volumes: []types.ServiceVolumeConfig{{Type: "bind", Source: "!data/db", Target: "/var/lib/postgresql/data"}},
+		},
+		&types.ServiceConfig{
+			Name:  "web",
+			Image: "nextcloud",
+			Ports: []types.ServicePortConfig{{Target: 80, Published: 8080}},
+		},
+		&types.ServiceConfig{Name: "worker", Image: "nextcloud"},
+	)
+
+	manifests, err := Convert(a, DefaultOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	services := map[string]*Service{}
+	workloads := map[string]*Workload{}
+	for _, m := range manifests {
+		switch r := m.Resource.(type) {
+		case *Service:
+			services[r.Metadata.Name] = r
+		case *Workload:
+			workloads[r.Metadata.Name] = r
+		}
+	}
+
+	tests := []struct {
+		name      string
+		clusterIP string
+		ports     []uint32
+	}{
+		{"test-db", "None", []uint32{5432}},
+		{"test-web", "", []uint32{8080}},
+		{"test-worker", "None", []uint32{}},
+	}
+	for _, tt := range tests {
+		s, ok := services[tt.name]
+		if !ok {
+			t.Errorf("%s: no service", tt.name)
+			continue
+		}
+		if s.Spec.ClusterIP != tt.clusterIP {
+			t.Errorf("%s: clusterIP = %q, want %q", tt.name, s.Spec.ClusterIP, tt.clusterIP)
+		}
+		if len(s.Spec.Ports) != len(tt.ports) {
+			t.Errorf("%s: ports = %+v, want %v", tt.name, s.Spec.Ports, tt.ports)
+			continue
+		}
+		for i, port := range tt.ports {
+			if s.Spec.Ports[i].Port != port {
+				t.Errorf("%s: port = %d, want %d", tt.name, s.Spec.Ports[i].Port, port)
+			}
+		}
+	}
+
+	db := workloads["test-db"]
+	if db == nil || db.Kind != "StatefulSet" || services[db.Spec.ServiceName] == nil {
+		t.Errorf("stateful set has no governing service: %+v", db)
+	}
+}
+
+func TestSplitEnvironment(t *testing.T) {
+	password := "changeme"
+	token := "abc"
+	tz := "UTC"
+	a := project.NewApplication()
+	a.Name = "Test"
+	service := &types.ServiceConfig{
+		Name:        "test",
+		Environment: types.MappingWithEquals{"PASSWORD": &password, "TOKEN": &token, "TZ": &tz, "EMPTY": nil},
+	}
+	a.Parameters = append(a.Parameters,
+		&project.Parameter{Type: project.ParameterTypeSecret, Target: "PASSWORD"},
+		&project.Parameter{Type: project.ParameterTypeVariable, Target: "TOKEN", Mask: true},
+		&project.Parameter{Type: project.ParameterTypeVariable, Target: "TZ"},
+	)
+
+	data, secrets := SplitEnvironment(a, service)
+	if len(data) != 1 || data["TZ"] != tz {
+		t.Errorf("data = %v, want TZ only", data)
+	}
+	if len(secrets) != 2 || secrets["PASSWORD"] != password || secrets["TOKEN"] != token {
+		t.Errorf("secrets = %v, want PASSWORD and TOKEN", secrets)
+	}
+}
+
+func TestServiceName(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Nextcloud"
+
+	tests := []struct {
+		service string
+		want    string
+	}{
+		{"db", "nextcloud-db"},
+		{"nextcloud", "nextcloud"},
+		{"", "nextcloud"},
+		{"Web_App", "nextcloud-web-app"},
+	}
+	for _, tt := range tests {
+		if got := ServiceName(a, &types.ServiceConfig{Name: tt.service}); got != tt.want {
+			t.Errorf("ServiceName(%q) = %q, want %q", tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestConvertRenamesHosts(t *testing.T) {
+	host := "db:5432"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services,
+		&types.ServiceConfig{Name: "db", Image: "postgres"},
+		&types.ServiceConfig{Name: "web", Image: "nextcloud", Environment: types.MappingWithEquals{"DB_HOST": &host}},
+	)
+
+	manifests, err := Convert(a, DefaultOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range manifests {
+		if c, ok := m.Resource.(*ConfigMap); ok && c.Metadata.Name == "test-web-env" {
+			if c.Data["DB_HOST"] != "test-db:5432" {
+				t.Errorf("DB_HOST = %q, want test-db:5432", c.Data["DB_HOST"])
+			}
+			return
+		}
+	}
+	t.Errorf("no config map of web")
+}
+
+func TestConvertPortNames(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:  "web",
+		Image: "nginx",
+		Ports: []types.ServicePortConfig{{Target: 80, Published: 8080}, {Target: 80, Published: 8081}, {Target: 53, Protocol: "udp"}},
+	})
+
+	manifests, err := Convert(a, DefaultOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	containerPorts, servicePorts := []string{}, []string{}
+	for _, m := range manifests {
+		switch r := m.Resource.(type) {
+		case *Service:
+			for _, p := range r.Spec.Ports {
+				servicePorts = append(servicePorts, p.Name)
+			}
+		case *Workload:
+			for _, p := range r.Spec.Template.Spec.Containers[0].Ports {
+				containerPorts = append(containerPorts, p.Name)
+			}
+		}
+	}
+
+	if strings.Join(containerPorts, ",") != "tcp-80,udp-53" {
+		t.Errorf("container ports = %v, want [tcp-80 udp-53]", containerPorts)
+	}
+	if strings.Join(servicePorts, ",") != "tcp-80,tcp-80-8081" {
+		t.Errorf("service ports = %v, want [tcp-80 tcp-80-8081]", servicePorts)
+	}
+}
@@ -0,0 +1,177 @@
+package kubernetes
+
+// Object is the common head of kubernetes resources
+type Object struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Metadata   Metadata `yaml:"metadata"`
+}
+
+// Metadata of resource
+type Metadata struct {
+	Name      string            `yaml:"name,omitempty"`
+	Namespace string            `yaml:"namespace,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+}
+
+// Workload is deployment or stateful set
+type Workload struct {
+	Object `yaml:",inline"`
+	Spec   WorkloadSpec `yaml:"spec"`
+}
+
+// WorkloadSpec of deployment and stateful set
+type WorkloadSpec struct {
+	ServiceName string      `yaml:"serviceName,omitempty"`
+	Replicas    int         `yaml:"replicas"`
+	Selector    Selector    `yaml:"selector"`
+	Template    PodTemplate `yaml:"template"`
+}
+
+// Selector by labels
+type Selector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+// PodTemplate of workload
+type PodTemplate struct {
+	Metadata Metadata `yaml:"metadata"`
+	Spec     PodSpec  `yaml:"spec"`
+}
+
+// PodSpec of pod
+type PodSpec struct {
+	HostNetwork bool        `yaml:"hostNetwork,omitempty"`
+	Hostname    string      `yaml:"hostname,omitempty"`
+	Containers  []Container `yaml:"containers"`
+	Volumes     []Volume    `yaml:"volumes,omitempty"`
+}
+
+// Container of pod
+type Container struct {
+	Name            string           `yaml:"name"`
+	Image           string           `yaml:"image"`
+	Command         []string         `yaml:"command,omitempty"`
+	Args            []string         `yaml:"args,omitempty"`
+	WorkingDir      string           `yaml:"workingDir,omitempty"`
+	Ports           []ContainerPort  `yaml:"ports,omitempty"`
+	EnvFrom         []EnvFromSource  `yaml:"envFrom,omitempty"`
+	VolumeMounts    []VolumeMount    `yaml:"volumeMounts,omitempty"`
+	SecurityContext *SecurityContext `yaml:"securityContext,omitempty"`
+}
+
+// ContainerPort of container
+type ContainerPort struct {
+	Name          string `yaml:"name,omitempty"`
+	ContainerPort uint32 `yaml:"containerPort"`
+	Protocol      string `yaml:"protocol"`
+}
+
+// EnvFromSource is a config map or secret
+type EnvFromSource struct {
+	ConfigMapRef *LocalObjectReference `yaml:"configMapRef,omitempty"`
+	SecretRef    *LocalObjectReference `yaml:"secretRef,omitempty"`
+}
+
+// LocalObjectReference by name
+type LocalObjectReference struct {
+	Name string `yaml:"name"`
+}
+
+// VolumeMount of container
+type VolumeMount struct {
+	Name      string `yaml:"name"`
+	MountPath string `yaml:"mountPath"`
+	ReadOnly  bool   `yaml:"readOnly,omitempty"`
+}
+
+// SecurityContext of container
+type SecurityContext struct {
+	Privileged   bool          `yaml:"privileged,omitempty"`
+	Capabilities *Capabilities `yaml:"capabilities,omitempty"`
+}
+
+// Capabilities of container
+type Capabilities struct {
+	Add  []string `yaml:"add,omitempty"`
+	Drop []string `yaml:"drop,omitempty"`
+}
+
+// Volume of pod
+type Volume struct {
+	Name                  string                       `yaml:"name"`
+	PersistentVolumeClaim *PersistentVolumeClaimSource `yaml:"persistentVolumeClaim,omitempty"`
+	HostPath              *HostPathSource              `yaml:"hostPath,omitempty"`
+}
+
+// PersistentVolumeClaimSource of volume
+type PersistentVolumeClaimSource struct {
+	ClaimName string `yaml:"claimName"`
+}
+
+// HostPathSource of volume
+type HostPathSource struct {
+	Path string `yaml:"path"`
+	Type string `yaml:"type,omitempty"`
+}
+
+// Service resource
+type Service struct {
+	Object `yaml:",inline"`
+	Spec   ServiceSpec `yaml:"spec"`
+}
+
+// ServiceSpec of service
+type ServiceSpec struct {
+	Type      string            `yaml:"type,omitempty"`
+	ClusterIP string            `yaml:"clusterIP,omitempty"`
+	Selector  map[string]string `yaml:"selector"`
+	Ports     []ServicePort     `yaml:"ports"`
+}
+
+// ServicePort of service
+type ServicePort struct {
+	Name       string `yaml:"name"`
+	Port       uint32 `yaml:"port"`
+	TargetPort uint32 `yaml:"targetPort"`
+	Protocol   string `yaml:"protocol"`
+}
+
+// PersistentVolumeClaim resource
+type PersistentVolumeClaim struct {
+	Object `yaml:",inline"`
+	Spec   PersistentVolumeClaimSpec `yaml:"spec"`
+}
+
+// PersistentVolumeClaimSpec of persistent volume claim
+type PersistentVolumeClaimSpec struct {
+	AccessModes      []string             `yaml:"accessModes"`
+	StorageClassName string               `yaml:"storageClassName,omitempty"`
+	Resources        ResourceRequirements `yaml:"resources"`
+}
+
+// ResourceRequirements of persistent volume claim
+type ResourceRequirements struct {
+	Requests map[string]string `yaml:"requests"`
+}
+
+// ConfigMap resource
+type ConfigMap struct {
+	Object `yaml:",inline"`
+	Data   map[string]string `yaml:"data"`
+}
+
+// Secret resource
+type Secret struct {
+	Object     `yaml:",inline"`
+	Type       string            `yaml:"type"`
+	StringData map[string]string `yaml:"stringData"`
+}
+
+// Kustomization of app directory
+type Kustomization struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Namespace  string   `yaml:"namespace,omitempty"`
+	Resources  []string `yaml:"resources"`
+}
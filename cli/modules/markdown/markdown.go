@@ -0,0 +1,199 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("markdown", Generater)
+}
+
+var defaultCategory = "Other"
+
+// Category of apps in catalog
+type Category struct {
+	Name string
+	Apps []*project.Application
+}
+
+// Option of catalog
+type Option struct {
+	// Link of app detail page, {id} is replaced by the app id
+	Link string
+	// Level of category headings
+	Level int
+}
+
+// Generater markdown catalog of apps grouped by category,
+// the region between the markers in readme is replaced in place
+//
+//	title: Apps
+//	url: https://example.com/store/
+//	link: app/{id}.html
+//	readme: README.md
+//	marker: apps
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("title", "Apps")
+	o.Config.SetDefault("link", "app/{id}.html")
+	o.Config.SetDefault("marker", "apps")
+
+	baseURL := o.Config.GetString("url")
+	if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	opt := Option{Link: baseURL + o.Config.GetString("link"), Level: 2}
+	categories := Categories(apps)
+
+	path := o.Project.GetDistPath("markdown")
+	os.MkdirAll(path, os.ModePerm)
+
+	doc := "# " + o.Config.GetString("title") + "\n\n" + Catalog(categories, opt)
+	filename := path + "/catalog.md"
+	err = ioutil.WriteFile(filename, []byte(doc), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("write catalog file [%s] error: %s", filename, err.Error())
+	}
+
+	readme := o.Config.GetString("readme")
+	if readme == "" {
+		return nil
+	}
+
+	payload, err := ioutil.ReadFile(readme)
+	if err != nil {
+		return fmt.Errorf("read readme %s error: %s", readme, err.Error())
+	}
+
+	opt.Level = 3
+	res, err := Replace(string(payload), o.Config.GetString("marker"), Catalog(categories, opt))
+	if err != nil {
+		return fmt.Errorf("update readme %s error: %s", readme, err.Error())
+	}
+
+	err = ioutil.WriteFile(readme, []byte(res), 0644)
+	if err != nil {
+		return fmt.Errorf("write readme file [%s] error: %s", readme, err.Error())
+	}
+
+	return nil
+}
+
+// Categories of apps by the first category, sorted by name and the default category is the last
+func Categories(apps []*project.Application) []*Category {
+	categories := []*Category{}
+	index := map[string]*Category{}
+
+	for _, a := range apps {
+		name := defaultCategory
+		if len(a.Category) > 0 && a.Category[0] != "" {
+			name = a.Category[0]
+		}
+
+		c, ok := index[name]
+		if !ok {
+			c = &Category{Name: name, Apps: []*project.Application{}}
+			index[name] = c
+			categories = append(categories, c)
+		}
+		c.Apps = append(c.Apps, a)
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].Name == defaultCategory || categories[j].Name == defaultCategory {
+			return categories[j].Name == defaultCategory && categories[i].Name != defaultCategory
+		}
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories
+}
+
+// Catalog of categories, a table per category
+func Catalog(categories []*Category, opt Option) string {
+	b := &strings.Builder{}
+	heading := strings.Repeat("#", opt.Level)
+
+	for _, c := range categories {
+		b.WriteString(heading + " " + c.Name + "\n\n")
+		b.WriteString("| | Name | Description | Image | Ports |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, a := range c.Apps {
+			b.WriteString("| " + strings.Join(Row(a, opt), " | ") + " |\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Row of app in table: icon, name, description, image and default ports
+func Row(a *project.Application, opt Option) []string {
+	icon := ""
+	if a.Icon != "" {
+		icon = "<img src=\"" + html.EscapeString(a.Icon) + "\" width=\"32\" height=\"32\">"
+	}
+
+	name := escape(a.Name)
+	if opt.Link != "" {
+		name = "[" + escapeLink(name) + "](" + strings.Replace(opt.Link, "{id}", a.GetID(), -1) + ")"
+	}
+
+	images := []string{}
+	ports := []string{}
+	for _, service := range a.Services {
+		images = append(images, "`"+service.Image+"`")
+		for _, port := range service.Ports {
+			p := strconv.Itoa(int(port.Target))
+			if port.Published != 0 {
+				p = strconv.Itoa(int(port.Published)) + ":" + p
+			}
+			if port.Protocol != "" && port.Protocol != "tcp" {
+				p += "/" + port.Protocol
+			}
+			ports = append(ports, p)
+		}
+	}
+
+	return []string{icon, name, escape(a.Description), strings.Join(images, "<br>"), strings.Join(ports, ", ")}
+}
+
+// Replace the region between <!-- marker:start --> and <!-- marker:end --> with content
+func Replace(doc, marker, content string) (string, error) {
+	start := "<!-- " + marker + ":start -->"
+	end := "<!-- " + marker + ":end -->"
+
+	i := strings.Index(doc, start)
+	if i < 0 {
+		return "", fmt.Errorf("marker %s not found", start)
+	}
+	j := strings.Index(doc[i:], end)
+	if j < 0 {
+		return "", fmt.Errorf("marker %s not found", end)
+	}
+
+	return doc[:i+len(start)] + "\n\n" + content + doc[i+j:], nil
+}
+
+// escape text in table cell
+func escape(s string) string {
+	s = strings.Replace(s, "|", "\\|", -1)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// escapeLink text of markdown link
+func escapeLink(s string) string {
+	return strings.NewReplacer("[", "\\[", "]", "\\]").Replace(s)
+}
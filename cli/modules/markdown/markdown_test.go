@@ -0,0 +1,63 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestReplace(t *testing.T) {
+	cases := []struct {
+		doc      string
+		expected string
+		err      bool
+	}{
+		{
+			doc:      "head\n<!-- apps:start -->\nold\n<!-- apps:end -->\ntail\n",
+			expected: "head\n<!-- apps:start -->\n\nnew\n<!-- apps:end -->\ntail\n",
+		},
+		{doc: "head\n<!-- apps:end -->\n", err: true},
+		{doc: "head\n<!-- apps:start -->\n", err: true},
+		{doc: "<!-- apps:end -->\nold\n<!-- apps:start -->\n", err: true},
+	}
+
+	for _, c := range cases {
+		res, err := Replace(c.doc, "apps", "new\n")
+		if c.err {
+			if err == nil {
+				t.Errorf("replace %q expected error, got %q", c.doc, res)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("replace %q error: %s", c.doc, err.Error())
+			continue
+		}
+		if res != c.expected {
+			t.Errorf("replace %q expected %q, got %q", c.doc, c.expected, res)
+		}
+	}
+}
+
+func TestRow(t *testing.T) {
+	a := project.NewApplication()
+	a.ID = "test"
+	a.Name = "Test [beta] | dev"
+	a.Description = "A\ntest | app"
+	a.Icon = `https://example.com/icon.png" onerror="alert(1)`
+
+	row := Row(a, Option{Link: "app/{id}.html"})
+
+	expected := []string{
+		`<img src="https://example.com/icon.png&#34; onerror=&#34;alert(1)" width="32" height="32">`,
+		`[Test \[beta\] \| dev](app/test.html)`,
+		`A test \| app`,
+		"",
+		"",
+	}
+	for i := range expected {
+		if row[i] != expected[i] {
+			t.Errorf("cell %d expected %q, got %q", i, expected[i], row[i])
+		}
+	}
+}
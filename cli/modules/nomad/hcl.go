@@ -0,0 +1,228 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// block of hcl file
+type block struct {
+	name   string
+	labels []string
+	attrs  [][2]interface{}
+	blocks []*block
+}
+
+func newBlock(name string, labels ...string) *block {
+	return &block{name: name, labels: labels}
+}
+
+func (b *block) attr(key string, value interface{}) *block {
+	b.attrs = append(b.attrs, [2]interface{}{key, value})
+	return b
+}
+
+func (b *block) add(child *block) *block {
+	b.blocks = append(b.blocks, child)
+	return b
+}
+
+func (b *block) write(sb *strings.Builder, indent string) {
+	sb.WriteString(indent + b.name)
+	for _, l := range b.labels {
+		sb.WriteString(" " + quoteHCL(l))
+	}
+	sb.WriteString(" {\n")
+
+	inner := indent + "  "
+	for _, a := range b.attrs {
+		sb.WriteString(inner + a[0].(string) + " = " + formatHCL(a[1], inner) + "\n")
+	}
+	for i, child := range b.blocks {
+		if i > 0 || len(b.attrs) > 0 {
+			sb.WriteString("\n")
+		}
+		child.write(sb, inner)
+	}
+
+	sb.WriteString(indent + "}\n")
+}
+
+// HCL of nomad job
+func HCL(job *Job) string {
+	j := newBlock("job", job.ID).
+		attr("name", job.Name).
+		attr("type", job.Type).
+		attr("datacenters", job.Datacenters)
+	if job.Namespace != "" {
+		j.attr("namespace", job.Namespace)
+	}
+	if len(job.Meta) > 0 {
+		j.attr("meta", job.Meta)
+	}
+	if u := job.Update; u != nil {
+		j.add(newBlock("update").
+			attr("max_parallel", u.MaxParallel).
+			attr("min_healthy_time", u.MinHealthyTime).
+			attr("healthy_deadline", u.HealthyDeadline).
+			attr("auto_revert", u.AutoRevert))
+	}
+
+	for _, g := range job.TaskGroups {
+		group := newBlock("group", g.Name).attr("count", g.Count)
+
+		for _, n := range g.Networks {
+			network := newBlock("network").attr("mode", n.Mode)
+			for _, p := range n.ReservedPorts {
+				network.add(newBlock("port", p.Label).attr("static", p.Value).attr("to", p.To))
+			}
+			for _, p := range n.DynamicPorts {
+				network.add(newBlock("port", p.Label).attr("to", p.To))
+			}
+			group.add(network)
+		}
+
+		names := []string{}
+		for name := range g.Volumes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			v := g.Volumes[name]
+			group.add(newBlock("volume", name).
+				attr("type", v.Type).
+				attr("source", v.Source).
+				attr("read_only", v.ReadOnly))
+		}
+
+		for _, t := range g.Tasks {
+			task := newBlock("task", t.Name).attr("driver", t.Driver)
+			if t.User != "" {
+				task.attr("user", t.User)
+			}
+
+			config := newBlock("config")
+			for _, key := range sortedKeys(t.Config) {
+				// mounts are blocks of docker driver
+				if mounts, ok := t.Config[key].([]map[string]string); ok && key == "mount" {
+					for _, m := range mounts {
+						mount := newBlock("mount")
+						for _, k := range sortedStringKeys(m) {
+							mount.attr(k, m[k])
+						}
+						config.add(mount)
+					}
+					continue
+				}
+				config.attr(key, t.Config[key])
+			}
+			task.add(config)
+
+			if len(t.Env) > 0 {
+				task.attr("env", t.Env)
+			}
+			for _, m := range t.VolumeMounts {
+				task.add(newBlock("volume_mount").
+					attr("volume", m.Volume).
+					attr("destination", m.Destination).
+					attr("read_only", m.ReadOnly))
+			}
+			if r := t.RestartPolicy; r != nil {
+				task.add(newBlock("restart").
+					attr("attempts", r.Attempts).
+					attr("interval", r.Interval).
+					attr("delay", r.Delay).
+					attr("mode", r.Mode))
+			}
+			if r := t.Resources; r != nil {
+				task.add(newBlock("resources").attr("cpu", r.CPU).attr("memory", r.MemoryMB))
+			}
+			group.add(task)
+		}
+
+		j.add(group)
+	}
+
+	sb := &strings.Builder{}
+	j.write(sb, "")
+	return sb.String()
+}
+
+func formatHCL(v interface{}, indent string) string {
+	switch value := v.(type) {
+	case string:
+		return quoteHCL(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case int:
+		return strconv.Itoa(value)
+	case time.Duration:
+		return quoteHCL(formatDuration(value))
+	case map[string]string:
+		fields := []string{}
+		for _, k := range sortedStringKeys(value) {
+			fields = append(fields, indent+"  "+quoteHCL(k)+" = "+quoteHCL(value[k]))
+		}
+		return "{\n" + strings.Join(fields, "\n") + "\n" + indent + "}"
+	case []string:
+		items := []string{}
+		for _, s := range value {
+			items = append(items, quoteHCL(s))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []map[string]string:
+		items := []string{}
+		for _, m := range value {
+			fields := []string{}
+			for _, k := range sortedStringKeys(m) {
+				fields = append(fields, indent+"    "+quoteHCL(k)+" = "+quoteHCL(m[k]))
+			}
+			items = append(items, indent+"  {\n"+strings.Join(fields, "\n")+"\n"+indent+"  }")
+		}
+		return "[\n" + strings.Join(items, ",\n") + "\n" + indent + "]"
+	}
+
+	return quoteHCL(fmt.Sprint(v))
+}
+
+// formatDuration without the zero units, 30m instead of 30m0s
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+
+	return s
+}
+
+// quoteHCL string, template sequences are escaped
+func quoteHCL(s string) string {
+	s = strings.Replace(strings.Replace(s, "${", "$${", -1), "%{", "%%{", -1)
+	return strconv.Quote(s)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,348 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("nomad", Generater)
+}
+
+// Volume modes of nomad job
+const (
+	VolumeModeBind       = "bind"
+	VolumeModeHostVolume = "host_volume"
+)
+
+// Option for nomad jobs
+type Option struct {
+	Datacenters []string
+	Namespace   string
+	// VolumeMode is bind for docker bind mounts under DataPath,
+	// host_volume for host volumes which need to be defined in client config
+	VolumeMode string
+	DataPath   string
+	CPU        int
+	MemoryMB   int
+}
+
+// DefaultOption of nomad jobs
+func DefaultOption() Option {
+	return Option{
+		Datacenters: []string{"dc1"},
+		VolumeMode:  VolumeModeBind,
+		DataPath:    "/opt/appdata",
+		CPU:         100,
+		MemoryMB:    256,
+	}
+}
+
+// Generater nomad job specs, hcl or json
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	opt := DefaultOption()
+	if v := o.Config.GetStringSlice("datacenters"); len(v) > 0 {
+		opt.Datacenters = v
+	}
+	opt.Namespace = o.Config.GetString("namespace")
+	if v := o.Config.GetString("volume_mode"); v != "" {
+		opt.VolumeMode = v
+	}
+	if v := o.Config.GetString("data_path"); v != "" {
+		opt.DataPath = strings.TrimSuffix(v, "/")
+	}
+	if v := o.Config.GetInt("cpu"); v > 0 {
+		opt.CPU = v
+	}
+	if v := o.Config.GetInt("memory"); v > 0 {
+		opt.MemoryMB = v
+	}
+
+	format := o.Config.GetString("format")
+	if format == "" {
+		format = "hcl"
+	}
+	if format != "hcl" && format != "json" {
+		return fmt.Errorf("unknown nomad job format %s", format)
+	}
+
+	path := o.Project.GetDistPath("templates", "nomad")
+	os.MkdirAll(path, os.ModePerm)
+
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		job := Convert(a, opt)
+		res := []byte(HCL(job))
+		if format == "json" {
+			res, err = json.MarshalIndent(map[string]*Job{"Job": job}, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshal nomad job %s error: %s", job.ID, err.Error())
+			}
+		}
+
+		filename := path + "/" + a.GetID() + ".nomad." + format
+		err = ioutil.WriteFile(filename, res, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write job file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Convert application to nomad job, a group per app and a docker task per service
+func Convert(a *project.Application, opt Option) *Job {
+	id := a.GetID()
+
+	group := &TaskGroup{
+		Name:    id,
+		Count:   1,
+		Volumes: map[string]*VolumeRequest{},
+		Tasks:   []*Task{},
+	}
+
+	network := &Network{Mode: "bridge"}
+	// port labels are shared by the tasks of group
+	used := map[string]bool{}
+	for _, service := range a.Services {
+		if service.NetworkMode == "host" {
+			network.Mode = "host"
+		}
+	}
+
+	for _, service := range a.Services {
+		task := &Task{
+			Name:          project.Slugify(service.Name),
+			Driver:        "docker",
+			User:          service.User,
+			Config:        map[string]interface{}{"image": service.Image},
+			Env:           map[string]string{},
+			VolumeMounts:  []*VolumeMount{},
+			RestartPolicy: restartPolicy(service.Restart),
+			Resources:     &Resources{CPU: opt.CPU, MemoryMB: opt.MemoryMB},
+		}
+		if task.Name == "" {
+			task.Name = id
+		}
+
+		if network.Mode == "host" {
+			task.Config["network_mode"] = "host"
+		}
+
+		labels := []string{}
+		for _, port := range service.Ports {
+			label := portLabel(port, used)
+			p := &Port{Label: label, Value: int(port.Published), To: int(port.Target)}
+			if p.Value != 0 {
+				network.ReservedPorts = append(network.ReservedPorts, p)
+			} else {
+				network.DynamicPorts = append(network.DynamicPorts, p)
+			}
+			labels = append(labels, label)
+		}
+		if len(labels) > 0 {
+			task.Config["ports"] = labels
+		}
+
+		binds := []string{}
+		for _, v := range service.Volumes {
+			if v.Type == "tmpfs" {
+				task.Config["mount"] = append(mounts(task.Config["mount"]), map[string]string{
+					"type":   "tmpfs",
+					"target": v.Target,
+				})
+				continue
+			}
+
+			source := volumeSource(a, v, opt)
+			if opt.VolumeMode == VolumeModeHostVolume {
+				name := id + "-" + project.Slugify(v.Target)
+				if v.Source != "" && !strings.HasPrefix(v.Source, "/") && !strings.HasPrefix(v.Source, project.DataPathPrefix) {
+					name = id + "-" + project.Slugify(v.Source)
+				}
+				group.Volumes[name] = &VolumeRequest{
+					Name:     name,
+					Type:     "host",
+					Source:   name,
+					ReadOnly: v.ReadOnly,
+				}
+				task.VolumeMounts = append(task.VolumeMounts, &VolumeMount{
+					Volume:      name,
+					Destination: v.Target,
+					ReadOnly:    v.ReadOnly,
+				})
+				continue
+			}
+
+			bind := source + ":" + v.Target
+			if v.ReadOnly {
+				bind += ":ro"
+			}
+			binds = append(binds, bind)
+		}
+		if len(binds) > 0 {
+			task.Config["volumes"] = binds
+		}
+
+		for name, value := range service.Environment {
+			if value != nil {
+				task.Env[name] = *value
+			}
+		}
+
+		// tasks of a group share the network namespace, other services are on localhost
+		hosts := []string{}
+		for _, s := range a.Services {
+			if s != service && s.Name != "" && network.Mode == "bridge" {
+				hosts = append(hosts, s.Name+":127.0.0.1")
+			}
+		}
+		if len(hosts) > 0 {
+			task.Config["extra_hosts"] = hosts
+		}
+
+		if len(service.Entrypoint) > 0 {
+			task.Config["entrypoint"] = []string(service.Entrypoint)
+		}
+		if len(service.Command) > 0 {
+			task.Config["command"] = service.Command[0]
+			if len(service.Command) > 1 {
+				task.Config["args"] = []string(service.Command[1:])
+			}
+		}
+		if service.Hostname != "" {
+			task.Config["hostname"] = service.Hostname
+		}
+		if service.Privileged {
+			task.Config["privileged"] = true
+		}
+		if len(service.CapAdd) > 0 {
+			task.Config["cap_add"] = service.CapAdd
+		}
+		if len(service.CapDrop) > 0 {
+			task.Config["cap_drop"] = service.CapDrop
+		}
+		if len(service.Devices) > 0 {
+			devices := []map[string]string{}
+			for _, d := range service.Devices {
+				parts := strings.Split(d, ":")
+				device := map[string]string{"host_path": parts[0], "container_path": parts[0]}
+				if len(parts) > 1 {
+					device["container_path"] = parts[1]
+				}
+				if len(parts) > 2 {
+					device["cgroup_permissions"] = parts[2]
+				}
+				devices = append(devices, device)
+			}
+			task.Config["devices"] = devices
+		}
+		if len(service.Labels) > 0 {
+			task.Config["labels"] = []map[string]string{service.Labels}
+		}
+
+		group.Tasks = append(group.Tasks, task)
+	}
+
+	if len(network.ReservedPorts) > 0 || len(network.DynamicPorts) > 0 || network.Mode == "host" {
+		group.Networks = []*Network{network}
+	}
+
+	job := &Job{
+		ID:          id,
+		Name:        id,
+		Type:        "service",
+		Namespace:   opt.Namespace,
+		Datacenters: opt.Datacenters,
+		Meta:        map[string]string{},
+		Update: &Update{
+			MaxParallel:     1,
+			MinHealthyTime:  10 * time.Second,
+			HealthyDeadline: 5 * time.Minute,
+			AutoRevert:      true,
+		},
+		TaskGroups: []*TaskGroup{group},
+	}
+	if a.Name != "" {
+		job.Meta["name"] = a.Name
+	}
+
+	return job
+}
+
+// portLabel of port unique in used, duplicated labels are suffixed
+// with the published port, then with their order
+func portLabel(port types.ServicePortConfig, used map[string]bool) string {
+	label := "port_" + strconv.Itoa(int(port.Target))
+	if port.Protocol != "" && port.Protocol != "tcp" {
+		label += "_" + port.Protocol
+	}
+	if used[label] && port.Published != 0 {
+		label += "_" + strconv.Itoa(int(port.Published))
+	}
+	base := label
+	for i := 2; used[label]; i++ {
+		label = base + "_" + strconv.Itoa(i)
+	}
+	used[label] = true
+
+	return label
+}
+
+// restartPolicy of compose restart, nomad keeps restarting unless restart is no
+func restartPolicy(restart string) *RestartPolicy {
+	policy := &RestartPolicy{
+		Attempts: 2,
+		Interval: 30 * time.Minute,
+		Delay:    15 * time.Second,
+		Mode:     "fail",
+	}
+
+	switch restart {
+	case "no":
+		policy.Attempts = 0
+	case "always", "unless-stopped":
+		policy.Mode = "delay"
+	}
+
+	return policy
+}
+
+func volumeSource(a *project.Application, v types.ServiceVolumeConfig, opt Option) string {
+	root := opt.DataPath + "/" + a.GetID()
+	switch {
+	case v.Source == project.DataPathPrefix || strings.HasPrefix(v.Source, project.DataPathPrefix+"/"):
+		return project.ResolveDataPath(v.Source, opt.DataPath)
+	case v.Source == "":
+		return root + "/" + project.Slugify(v.Target)
+	case !strings.HasPrefix(v.Source, "/"):
+		// named volume
+		return root + "/" + v.Source
+	}
+
+	return v.Source
+}
+
+func mounts(v interface{}) []map[string]string {
+	if m, ok := v.([]map[string]string); ok {
+		return m
+	}
+
+	return []map[string]string{}
+}
@@ -0,0 +1,85 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestConvertPortLabels(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services,
+		&types.ServiceConfig{Name: "web", Image: "foo/web", Ports: []types.ServicePortConfig{{Target: 80, Published: 8080}, {Target: 53}, {Target: 53, Protocol: "udp"}}},
+		&types.ServiceConfig{Name: "admin", Image: "foo/admin", Ports: []types.ServicePortConfig{{Target: 80, Published: 8081}, {Target: 53}}},
+	)
+
+	job := Convert(a, DefaultOption())
+
+	labels := []string{}
+	for _, task := range job.TaskGroups[0].Tasks {
+		labels = append(labels, strings.Join(task.Config["ports"].([]string), ","))
+	}
+	expected := []string{"port_80,port_53,port_53_udp", "port_80_8081,port_53_2"}
+	if strings.Join(labels, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected port labels %v, got %v", expected, labels)
+	}
+
+	network := job.TaskGroups[0].Networks[0]
+	if len(network.ReservedPorts) != 2 || network.ReservedPorts[1].Label != "port_80_8081" || network.ReservedPorts[1].Value != 8081 {
+		t.Errorf("unexpected reserved ports %+v", network.ReservedPorts)
+	}
+}
+
+func TestVolumeSource(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	opt := DefaultOption()
+
+	cases := map[string]types.ServiceVolumeConfig{
+		"/opt/appdata/test/config":    {Source: "!data/test/config", Target: "/config"},
+		"/opt/appdata/test/!database": {Source: "!database", Target: "/db"},
+		"/opt/appdata/test/cache":     {Source: "cache", Target: "/cache"},
+		"/opt/appdata/test/var-lib":   {Target: "/var/lib"},
+		"/etc/localtime":              {Source: "/etc/localtime", Target: "/etc/localtime"},
+	}
+	for expected, v := range cases {
+		if res := volumeSource(a, v, opt); res != expected {
+			t.Errorf("source of %+v expected %s, got %s", v, expected, res)
+		}
+	}
+}
+
+func TestHCL(t *testing.T) {
+	tz := "UTC"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test:1.0",
+		Restart:     "unless-stopped",
+		Environment: types.MappingWithEquals{"TZ": &tz},
+		Ports:       []types.ServicePortConfig{{Target: 8080, Published: 8081}},
+		Volumes:     []types.ServiceVolumeConfig{{Source: "!data/test", Target: "/config", ReadOnly: true}},
+	})
+
+	res := HCL(Convert(a, DefaultOption()))
+
+	for _, expected := range []string{
+		`job "test" {`,
+		`datacenters = ["dc1"]`,
+		`group "test" {`,
+		"port \"port_8080\" {\n        static = 8081\n        to = 8080",
+		`task "test" {`,
+		`image = "foo/test:1.0"`,
+		`"/opt/appdata/test:/config:ro"`,
+		`"TZ" = "UTC"`,
+		`mode = "delay"`,
+	} {
+		if !strings.Contains(res, expected) {
+			t.Errorf("%s is missing:\n%s", expected, res)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package nomad
+
+import "time"
+
+// Job is the nomad job in api json format
+type Job struct {
+	ID          string            `json:"ID"`
+	Name        string            `json:"Name"`
+	Type        string            `json:"Type"`
+	Namespace   string            `json:"Namespace,omitempty"`
+	Datacenters []string          `json:"Datacenters"`
+	Meta        map[string]string `json:"Meta,omitempty"`
+	Update      *Update           `json:"Update,omitempty"`
+	TaskGroups  []*TaskGroup      `json:"TaskGroups"`
+}
+
+// Update strategy of job
+type Update struct {
+	MaxParallel     int           `json:"MaxParallel"`
+	MinHealthyTime  time.Duration `json:"MinHealthyTime"`
+	HealthyDeadline time.Duration `json:"HealthyDeadline"`
+	AutoRevert      bool          `json:"AutoRevert"`
+}
+
+// TaskGroup of job, one group per app
+type TaskGroup struct {
+	Name     string                    `json:"Name"`
+	Count    int                       `json:"Count"`
+	Networks []*Network                `json:"Networks,omitempty"`
+	Volumes  map[string]*VolumeRequest `json:"Volumes,omitempty"`
+	Tasks    []*Task                   `json:"Tasks"`
+}
+
+// Network of task group
+type Network struct {
+	Mode          string  `json:"Mode"`
+	ReservedPorts []*Port `json:"ReservedPorts,omitempty"`
+	DynamicPorts  []*Port `json:"DynamicPorts,omitempty"`
+}
+
+// Port of network, value is the static port, to is the container port
+type Port struct {
+	Label string `json:"Label"`
+	Value int    `json:"Value,omitempty"`
+	To    int    `json:"To,omitempty"`
+}
+
+// VolumeRequest of host volume
+type VolumeRequest struct {
+	Name     string `json:"Name"`
+	Type     string `json:"Type"`
+	Source   string `json:"Source"`
+	ReadOnly bool   `json:"ReadOnly"`
+}
+
+// RestartPolicy of task
+type RestartPolicy struct {
+	Attempts int           `json:"Attempts"`
+	Interval time.Duration `json:"Interval"`
+	Delay    time.Duration `json:"Delay"`
+	Mode     string        `json:"Mode"`
+}
+
+// Task of task group, one task per service
+type Task struct {
+	Name          string                 `json:"Name"`
+	Driver        string                 `json:"Driver"`
+	User          string                 `json:"User,omitempty"`
+	Config        map[string]interface{} `json:"Config"`
+	Env           map[string]string      `json:"Env,omitempty"`
+	VolumeMounts  []*VolumeMount         `json:"VolumeMounts,omitempty"`
+	RestartPolicy *RestartPolicy         `json:"RestartPolicy,omitempty"`
+	Resources     *Resources             `json:"Resources"`
+}
+
+// VolumeMount of task
+type VolumeMount struct {
+	Volume      string `json:"Volume"`
+	Destination string `json:"Destination"`
+	ReadOnly    bool   `json:"ReadOnly"`
+}
+
+// Resources of task
+type Resources struct {
+	CPU      int `json:"CPU"`
+	MemoryMB int `json:"MemoryMB"`
+}
@@ -0,0 +1,243 @@
+package paas
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// Template is the paas model of application shared by caprover and coolify generaters
+type Template struct {
+	ID            string
+	Name          string
+	Description   string
+	Documentation string
+	Icon          string
+	Tags          []string
+	// Main service serves the web ui on Port
+	Main string
+	Port uint32
+	// Version is the tag of the main image, empty when pinned by digest
+	Version   string
+	Services  []*Service
+	Variables []*Variable
+}
+
+// Service of template
+type Service struct {
+	Name        string
+	Image       string
+	Command     []string
+	Environment []*Env
+	Ports       []string
+	Volumes     []*Volume
+	DependsOn   []string
+	Restart     string
+	CapAdd      []string
+}
+
+// Env of service, Variable is set when the value comes from a template parameter
+type Env struct {
+	Name     string
+	Value    string
+	Variable *Variable
+}
+
+// Volume of service, Name of the app scoped named volume or Source of host path
+type Volume struct {
+	Name     string
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// Variable of template, from the variable and secret parameters
+type Variable struct {
+	Name        string
+	Label       string
+	Description string
+	Default     string
+	Secret      bool
+	Required    bool
+}
+
+// NewTemplate of application
+func NewTemplate(a *project.Application) *Template {
+	t := &Template{
+		ID:          a.GetID(),
+		Name:        a.Name,
+		Description: a.Description,
+		Icon:        a.Icon,
+		Tags:        []string{},
+		Services:    []*Service{},
+		Variables:   []*Variable{},
+	}
+	for _, c := range a.Category {
+		if c != "" {
+			t.Tags = append(t.Tags, strings.ToLower(c))
+		}
+	}
+	for _, typ := range []string{project.LinkTypeProject, project.LinkTypeSupport, project.LinkTypeRegistry} {
+		if l := a.GetLink(typ); l != nil {
+			t.Documentation = l.URL
+			break
+		}
+	}
+
+	if len(a.Services) > 0 {
+		t.Main = a.Services[0].Name
+	}
+	if a.WebUI != nil {
+		t.Port = a.WebUI.Port
+		for _, s := range a.Services {
+			for _, port := range s.Ports {
+				if port.Target == a.WebUI.Port {
+					t.Main = s.Name
+				}
+			}
+		}
+	}
+
+	variables := map[string]*Variable{}
+	for _, s := range a.Services {
+		service := &Service{
+			Name:        s.Name,
+			Image:       s.Image,
+			Command:     s.Command,
+			Environment: []*Env{},
+			Ports:       []string{},
+			Volumes:     []*Volume{},
+			DependsOn:   s.DependsOn,
+			Restart:     s.Restart,
+			CapAdd:      s.CapAdd,
+		}
+
+		image := project.ParseImage(s.Image)
+		if s.Name == t.Main && image.Digest == "" {
+			t.Version = image.Tag
+			service.Image = shortName(image)
+		}
+
+		for _, port := range s.Ports {
+			if s.Name == t.Main && port.Target == t.Port {
+				continue
+			}
+			p := port.Target
+			if port.Published != 0 {
+				p = port.Published
+			}
+			ref := strconv.Itoa(int(p)) + ":" + strconv.Itoa(int(port.Target))
+			if port.Protocol != "" && port.Protocol != "tcp" {
+				ref += "/" + port.Protocol
+			}
+			service.Ports = append(service.Ports, ref)
+		}
+
+		for _, v := range s.Volumes {
+			switch {
+			case v.Type == "tmpfs":
+			case strings.HasPrefix(v.Source, project.DataPathPrefix):
+				service.Volumes = append(service.Volumes, &Volume{Name: volumeName(v.Source, t.ID), Target: v.Target, ReadOnly: v.ReadOnly})
+			case v.Source != "" && !strings.HasPrefix(v.Source, "/"):
+				service.Volumes = append(service.Volumes, &Volume{Name: project.Slugify(v.Source), Target: v.Target, ReadOnly: v.ReadOnly})
+			case v.Source != "":
+				service.Volumes = append(service.Volumes, &Volume{Source: v.Source, Target: v.Target, ReadOnly: v.ReadOnly})
+			}
+		}
+
+		names := []string{}
+		for name := range s.Environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			env := &Env{Name: name}
+			if v := s.Environment[name]; v != nil {
+				env.Value = *v
+			}
+
+			p := a.GetParameter(project.ParameterTypeSecret, name)
+			if p == nil {
+				p = a.GetParameter(project.ParameterTypeVariable, name)
+			}
+			if p != nil {
+				variable, ok := variables[name]
+				if !ok {
+					variable = &Variable{
+						Name:        name,
+						Label:       p.GetLabel(),
+						Description: p.Description,
+						Default:     p.Default,
+						Secret:      p.IsSecret(),
+						Required:    p.Required,
+					}
+					if variable.Default == "" && !variable.Secret {
+						variable.Default = env.Value
+					}
+					variables[name] = variable
+					t.Variables = append(t.Variables, variable)
+				}
+				env.Variable = variable
+			}
+			service.Environment = append(service.Environment, env)
+		}
+
+		t.Services = append(t.Services, service)
+	}
+
+	return t
+}
+
+// volumeName of data volume, like !data/yarr/config to config,
+// the data root of app is named data
+func volumeName(source, id string) string {
+	name := strings.Trim(strings.TrimPrefix(source, project.DataPathPrefix), "/")
+	if name == id || strings.HasPrefix(name, id+"/") {
+		name = strings.TrimPrefix(name, id)
+	}
+	if name = project.Slugify(name); name == "" {
+		return "data"
+	}
+
+	return name
+}
+
+// shortName of image without tag, docker hub prefixes are removed
+func shortName(image *project.Image) string {
+	name := image.Repository
+	if image.Registry != project.DefaultRegistry {
+		return image.Registry + "/" + name
+	}
+
+	return strings.TrimPrefix(name, project.DefaultNamespace+"/")
+}
+
+// VolumeRef of volume with source in compose short syntax
+func VolumeRef(source string, v *Volume) string {
+	res := source + ":" + v.Target
+	if v.ReadOnly {
+		res += ":ro"
+	}
+
+	return res
+}
+
+// WriteFiles to the dist dir of generater, files are named by the relative path
+func WriteFiles(o *project.Operator, dir string, files map[string][]byte) error {
+	for name, content := range files {
+		filename := o.Project.GetDistPath(dir, name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		err := ioutil.WriteFile(filename, content, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write %s file [%s] error: %s", dir, filename, err.Error())
+		}
+	}
+
+	return nil
+}
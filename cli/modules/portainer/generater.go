@@ -47,17 +47,22 @@ type VolumeConfig struct {
 }
 
 // EnvironmentConfig for portainer template environment
-// TODO label and description
 type EnvironmentConfig struct {
 	Name        string `json:"name"`
 	Label       string `json:"label,omitempty"`
 	Default     string `json:"default,omitempty"`
 	Description string `json:"description,omitempty"`
+	Preset      bool   `json:"preset,omitempty"`
 }
 
 // Generater portainer template
 func Generater(o *project.Operator) error {
-	res, err := Convert(o.Project.Apps)
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	res, err := Convert(apps)
 	if err != nil {
 		return err
 	}
@@ -106,7 +111,7 @@ func ConvertApplication(a *project.Application) (*Template, error) {
 	t.Description = a.Overview
 	t.Categories = a.Category
 	t.Platform = "linux"
-	t.Note = a.Description
+	t.Note = a.GetNote()
 	t.Logo = a.Icon
 
 	t.Name = service.ContainerName
@@ -144,11 +149,21 @@ func ConvertApplication(a *project.Application) (*Template, error) {
 			if point != nil {
 				value = *point
 			}
-			t.Environment = append(t.Environment, EnvironmentConfig{
+			env := EnvironmentConfig{
 				Name:    name,
 				Label:   name,
 				Default: value,
-			})
+			}
+
+			if p := a.GetParameter(project.ParameterTypeVariable, name); p != nil {
+				env.Label = p.GetLabel()
+				env.Description = p.Description
+				// preset environment is not shown in portainer form,
+				// portainer templates can not mask values
+				env.Preset = p.IsAdvanced()
+			}
+
+			t.Environment = append(t.Environment, env)
 		}
 	}
 
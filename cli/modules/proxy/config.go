@@ -0,0 +1,57 @@
+package proxy
+
+import "strings"
+
+// Caddyfile of routes, auth imports the (auth) snippet defined by user
+func Caddyfile(routes []*Route, opt Option) string {
+	b := &strings.Builder{}
+
+	for i, r := range routes {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(r.Domain + " {\n")
+		if opt.Auth {
+			b.WriteString("\timport auth\n")
+		}
+		if r.Scheme == "https" {
+			// web uis served over https usually have self-signed certificates
+			b.WriteString("\treverse_proxy https://" + upstream(r, opt) + " {\n")
+			b.WriteString("\t\ttransport http {\n\t\t\ttls_insecure_skip_verify\n\t\t}\n\t}\n")
+		} else {
+			b.WriteString("\treverse_proxy " + upstream(r, opt) + "\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+// Nginx server block of route, auth includes auth.conf defined by user
+func Nginx(r *Route, opt Option) string {
+	b := &strings.Builder{}
+
+	b.WriteString("server {\n")
+	b.WriteString("    listen 80;\n")
+	b.WriteString("    server_name " + r.Domain + ";\n\n")
+	b.WriteString("    location / {\n")
+	if opt.Auth {
+		b.WriteString("        include auth.conf;\n")
+	}
+	scheme := "http"
+	if r.Scheme == "https" {
+		scheme = "https"
+	}
+	b.WriteString("        proxy_pass " + scheme + "://" + upstream(r, opt) + ";\n")
+	b.WriteString("        proxy_http_version 1.1;\n")
+	b.WriteString("        proxy_set_header Host $host;\n")
+	b.WriteString("        proxy_set_header X-Real-IP $remote_addr;\n")
+	b.WriteString("        proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n")
+	b.WriteString("        proxy_set_header X-Forwarded-Proto $scheme;\n")
+	b.WriteString("        proxy_set_header Upgrade $http_upgrade;\n")
+	b.WriteString("        proxy_set_header Connection \"upgrade\";\n")
+	b.WriteString("    }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -0,0 +1,258 @@
+package proxy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterEncoder("docker-compose-traefik", Encoder)
+	project.RegisterGenerater("proxy", Generater)
+}
+
+// Option for reverse proxy configs
+type Option struct {
+	// Domain pattern, {app} is replaced by the app id
+	Domain string
+	// Network shared by the proxy and the apps
+	Network string
+	// EntryPoint and CertResolver of traefik routers
+	EntryPoint   string
+	CertResolver string
+	// Auth enables the auth middleware, Middleware is the traefik middleware,
+	// caddy imports the (auth) snippet and nginx includes auth.conf
+	Auth       bool
+	Middleware string
+	// Upstream host of caddy and nginx, the published port is used when set,
+	// otherwise the service on the shared network
+	Upstream string
+}
+
+// DefaultOption of reverse proxy configs
+func DefaultOption() Option {
+	return Option{
+		Domain:     "{app}.home.example",
+		Network:    "proxy",
+		EntryPoint: "websecure",
+		Middleware: "auth@file",
+	}
+}
+
+// Route of app web ui
+type Route struct {
+	ID      string
+	Domain  string
+	Service string
+	// Scheme of the web ui, http or https
+	Scheme string
+	Port   uint32
+	// Published port on host, 0 when not published
+	Published uint32
+}
+
+// Encoder for docker-compose.yml with traefik labels
+func Encoder(a *project.Application) ([]byte, error) {
+	cfg, err := Compose(a, DefaultOption())
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(cfg)
+}
+
+// Generater reverse proxy configs of apps with web ui
+//
+//	domain: "{app}.home.example"
+//	network: proxy
+//	entrypoint: websecure
+//	cert_resolver: letsencrypt
+//	auth: true
+//	middleware: auth@file
+//	upstream: 192.168.1.10
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	opt := DefaultOption()
+	if v := o.Config.GetString("domain"); v != "" {
+		opt.Domain = v
+	}
+	if v := o.Config.GetString("network"); v != "" {
+		opt.Network = v
+	}
+	if v := o.Config.GetString("entrypoint"); v != "" {
+		opt.EntryPoint = v
+	}
+	if v := o.Config.GetString("middleware"); v != "" {
+		opt.Middleware = v
+	}
+	opt.CertResolver = o.Config.GetString("cert_resolver")
+	opt.Auth = o.Config.GetBool("auth")
+	opt.Upstream = o.Config.GetString("upstream")
+
+	routes := []*Route{}
+	path := o.Project.GetDistPath("proxy")
+	os.MkdirAll(path+"/traefik", os.ModePerm)
+	os.MkdirAll(path+"/caddy", os.ModePerm)
+	os.MkdirAll(path+"/nginx", os.ModePerm)
+
+	for _, a := range apps {
+		r := NewRoute(a, opt)
+		if r == nil {
+			continue
+		}
+
+		cfg, err := Compose(a, opt)
+		if err != nil {
+			return fmt.Errorf("convert application %s to compose with labels error: %s", a.Name, err.Error())
+		}
+
+		res, err := yaml.Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("marshal compose %s error: %s", a.Name, err.Error())
+		}
+
+		filename := path + "/traefik/" + r.ID + ".yml"
+		err = ioutil.WriteFile(filename, res, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write compose file [%s] error: %s", filename, err.Error())
+		}
+
+		// caddy and nginx on the upstream host reach published ports only
+		if opt.Upstream != "" && r.Published == 0 {
+			fmt.Printf("proxy generater: web ui port of %s is not published, skip caddy and nginx\n", a.Name)
+			continue
+		}
+		routes = append(routes, r)
+
+		filename = path + "/nginx/" + r.ID + ".conf"
+		err = ioutil.WriteFile(filename, []byte(Nginx(r, opt)), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write nginx file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	filename := path + "/caddy/Caddyfile"
+	err = ioutil.WriteFile(filename, []byte(Caddyfile(routes, opt)), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("write caddy file [%s] error: %s", filename, err.Error())
+	}
+
+	return nil
+}
+
+// NewRoute of app web ui, nil when no web ui
+func NewRoute(a *project.Application, opt Option) *Route {
+	if a.WebUI == nil || len(a.Services) == 0 {
+		return nil
+	}
+
+	id := a.GetID()
+	r := &Route{
+		ID:      id,
+		Domain:  strings.Replace(opt.Domain, "{app}", id, -1),
+		Service: a.Services[0].Name,
+		Scheme:  "http",
+		Port:    a.WebUI.Port,
+	}
+	if a.WebUI.Scheme != "" {
+		r.Scheme = a.WebUI.Scheme
+	}
+
+	for _, service := range a.Services {
+		for _, port := range service.Ports {
+			if port.Target == a.WebUI.Port {
+				r.Service = service.Name
+				r.Published = port.Published
+				return r
+			}
+		}
+	}
+
+	return r
+}
+
+// Compose of app with traefik labels on the web ui service,
+// which joins the external proxy network
+func Compose(a *project.Application, opt Option) (*types.Config, error) {
+	if len(a.Services) == 0 {
+		return nil, fmt.Errorf("no service found")
+	}
+
+	cfg := &types.Config{
+		Version:  "3.0",
+		Services: types.Services{},
+	}
+
+	r := NewRoute(a, opt)
+	for _, s := range a.Services {
+		service := *s
+		if r != nil && service.Name == r.Service {
+			service.Labels = Labels(r, s.Labels, opt)
+			if service.NetworkMode != "host" {
+				service.Networks = map[string]*types.ServiceNetworkConfig{opt.Network: nil}
+				for name, n := range s.Networks {
+					service.Networks[name] = n
+				}
+				if len(s.Networks) == 0 {
+					service.Networks["default"] = nil
+				}
+				cfg.Networks = map[string]types.NetworkConfig{
+					opt.Network: {External: types.External{External: true}},
+				}
+			}
+		}
+		cfg.Services = append(cfg.Services, service)
+	}
+
+	return cfg, nil
+}
+
+// Labels of traefik router and service, merged with the origin labels
+func Labels(r *Route, origin types.Labels, opt Option) types.Labels {
+	labels := types.Labels{}
+	for k, v := range origin {
+		labels[k] = v
+	}
+
+	router := "traefik.http.routers." + r.ID
+	labels["traefik.enable"] = "true"
+	labels[router+".rule"] = "Host(`" + r.Domain + "`)"
+	labels["traefik.http.services."+r.ID+".loadbalancer.server.port"] = strconv.Itoa(int(r.Port))
+	if r.Scheme == "https" {
+		labels["traefik.http.services."+r.ID+".loadbalancer.server.scheme"] = "https"
+	}
+	if opt.EntryPoint != "" {
+		labels[router+".entrypoints"] = opt.EntryPoint
+	}
+	if opt.CertResolver != "" {
+		labels[router+".tls.certresolver"] = opt.CertResolver
+	}
+	if opt.Auth && opt.Middleware != "" {
+		labels[router+".middlewares"] = opt.Middleware
+	}
+	if opt.Network != "" {
+		labels["traefik.docker.network"] = opt.Network
+	}
+
+	return labels
+}
+
+// upstream address of route for caddy and nginx, the service on the shared
+// network is used when the port is not published
+func upstream(r *Route, opt Option) string {
+	if opt.Upstream != "" && r.Published != 0 {
+		return opt.Upstream + ":" + strconv.Itoa(int(r.Published))
+	}
+
+	return r.Service + ":" + strconv.Itoa(int(r.Port))
+}
@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"github.com/yankghjh/selfhosted_store/cli/project/projecttest"
+)
+
+// webPorts of the web service, the web ui is not on the first port
+var webPorts = []types.ServicePortConfig{
+	{Target: 139, Published: 139, Protocol: "tcp"},
+	{Target: 80, Published: 8080, Protocol: "tcp"},
+}
+
+func TestNewRoute(t *testing.T) {
+	a := projecttest.NewApp(projecttest.NewService("db", "postgres", nil), &types.ServiceConfig{Name: "web", Image: "foo/web", Ports: webPorts})
+	a.Name = "Web"
+	if r := NewRoute(a, DefaultOption()); r != nil {
+		t.Errorf("route of app without web ui = %+v, want nil", r)
+	}
+
+	a.WebUI = &project.WebUI{Scheme: "http", Port: 80}
+	r := NewRoute(a, DefaultOption())
+	if r == nil {
+		t.Fatal("route of app with web ui is nil")
+	}
+	if r.Domain != "web.home.example" || r.Service != "web" || r.Port != 80 || r.Published != 8080 {
+		t.Errorf("route = %+v", r)
+	}
+}
+
+func TestLabelsAndConfigs(t *testing.T) {
+	opt := DefaultOption()
+	opt.Auth = true
+	r := &Route{ID: "web", Domain: "web.home.example", Service: "web", Port: 80, Published: 8080}
+
+	labels := Labels(r, types.Labels{"com.example": "a"}, opt)
+	want := map[string]string{
+		"com.example":                                        "a",
+		"traefik.enable":                                     "true",
+		"traefik.http.routers.web.rule":                      "Host(`web.home.example`)",
+		"traefik.http.routers.web.middlewares":               "auth@file",
+		"traefik.http.services.web.loadbalancer.server.port": "80",
+	}
+	for k, v := range want {
+		if labels[k] != v {
+			t.Errorf("labels[%s] = %q, want %q", k, labels[k], v)
+		}
+	}
+
+	if caddy := Caddyfile([]*Route{r}, opt); !strings.Contains(caddy, "import auth") || !strings.Contains(caddy, "reverse_proxy web:80") {
+		t.Errorf("caddyfile:\n%s", caddy)
+	}
+
+	opt.Upstream = "192.168.1.10"
+	if nginx := Nginx(r, opt); !strings.Contains(nginx, "proxy_pass http://192.168.1.10:8080;") {
+		t.Errorf("nginx:\n%s", nginx)
+	}
+}
+
+func TestHTTPSWebUI(t *testing.T) {
+	opt := DefaultOption()
+	a := projecttest.NewApp(projecttest.NewService("db", "postgres", nil), &types.ServiceConfig{Name: "web", Image: "foo/web", Ports: webPorts})
+	a.Name = "Web"
+	a.WebUI = &project.WebUI{Scheme: "https", Port: 80}
+	r := NewRoute(a, opt)
+	if r.Scheme != "https" {
+		t.Fatalf("scheme = %q, want https", r.Scheme)
+	}
+
+	labels := Labels(r, nil, opt)
+	if labels["traefik.http.services.web.loadbalancer.server.scheme"] != "https" {
+		t.Errorf("labels have no https scheme: %v", labels)
+	}
+	if caddy := Caddyfile([]*Route{r}, opt); !strings.Contains(caddy, "reverse_proxy https://web:80 {") {
+		t.Errorf("caddyfile:\n%s", caddy)
+	}
+	if nginx := Nginx(r, opt); !strings.Contains(nginx, "proxy_pass https://web:80;") {
+		t.Errorf("nginx:\n%s", nginx)
+	}
+}
+
+func TestUpstream(t *testing.T) {
+	opt := DefaultOption()
+	tests := []struct {
+		upstream  string
+		published uint32
+		want      string
+	}{
+		{"", 8080, "web:80"},
+		{"192.168.1.10", 8080, "192.168.1.10:8080"},
+		{"192.168.1.10", 0, "web:80"},
+	}
+	for _, tt := range tests {
+		opt.Upstream = tt.upstream
+		r := &Route{Service: "web", Port: 80, Published: tt.published}
+		if got := upstream(r, opt); got != tt.want {
+			t.Errorf("upstream(%q, %d) = %q, want %q", tt.upstream, tt.published, got, tt.want)
+		}
+	}
+}
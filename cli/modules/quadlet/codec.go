@@ -0,0 +1,72 @@
+package quadlet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterEncoder("quadlet", Encoder)
+	project.RegisterGenerater("quadlet", Generater)
+}
+
+// Encoder for podman quadlet units, files are separated by name comments
+func Encoder(a *project.Application) ([]byte, error) {
+	if len(a.Services) == 0 {
+		return nil, fmt.Errorf("no service found")
+	}
+
+	out := ""
+	for i, u := range Convert(a, DefaultOption()) {
+		if i > 0 {
+			out += "\n"
+		}
+		out += "# " + u.Filename + "\n" + u.String()
+	}
+
+	return []byte(out), nil
+}
+
+// Generater podman units, one directory per app
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	opt := DefaultOption()
+	if v := o.Config.GetString("data_path"); v != "" {
+		opt.DataPath = v
+	}
+	if v := o.Config.GetString("mode"); v != "" {
+		opt.Mode = v
+	}
+	if o.Config.IsSet("auto_update") {
+		opt.AutoUpdate = o.Config.GetString("auto_update")
+	}
+	if opt.Mode != ModeQuadlet && opt.Mode != ModeSystemd {
+		return fmt.Errorf("unknown quadlet mode %s", opt.Mode)
+	}
+
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		path := o.Project.GetDistPath("templates", opt.Mode, a.GetID())
+		os.MkdirAll(path, os.ModePerm)
+
+		for _, u := range Convert(a, opt) {
+			filename := path + "/" + u.Filename
+			err := ioutil.WriteFile(filename, []byte(u.String()), os.ModePerm)
+			if err != nil {
+				return fmt.Errorf("write unit file [%s] error: %s", filename, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
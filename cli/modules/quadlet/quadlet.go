@@ -0,0 +1,370 @@
+package quadlet
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	dockerrun "github.com/yankghjh/selfhosted_store/cli/modules/docker-run"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// Mode of generated units
+const (
+	ModeQuadlet = "quadlet"
+	ModeSystemd = "systemd"
+)
+
+const autoUpdateLabel = "io.containers.autoupdate"
+
+// Option for podman units
+type Option struct {
+	DataPath string
+	// Mode is quadlet for .container units, systemd for podman generate systemd style services
+	Mode string
+	// AutoUpdate policy of podman auto-update, empty to disable
+	AutoUpdate string
+}
+
+// DefaultOption of podman units
+func DefaultOption() Option {
+	return Option{
+		DataPath:   dockerrun.DefaultDataPath,
+		Mode:       ModeQuadlet,
+		AutoUpdate: "registry",
+	}
+}
+
+// Convert application to units
+func Convert(a *project.Application, opt Option) []*Unit {
+	if opt.DataPath == "" {
+		opt.DataPath = dockerrun.DefaultDataPath
+	}
+	if opt.Mode == ModeSystemd {
+		return Services(a, opt)
+	}
+
+	return Containers(a, opt)
+}
+
+// Containers of application, .container units with .network and .volume units
+func Containers(a *project.Application, opt Option) []*Unit {
+	id := a.GetID()
+	units := []*Unit{}
+
+	network := ""
+	if len(a.Services) > 1 {
+		network = id + ".network"
+		u := NewUnit(network)
+		u.Section("Unit").Add("Description", appName(a)+" network")
+		u.Section("Network")
+		units = append(units, u)
+	}
+
+	volumes := map[string]bool{}
+	for _, service := range a.Services {
+		name := unitName(a, service)
+		u := NewUnit(name + ".container")
+		unit := u.Section("Unit").Add("Description", unitDescription(a, service))
+		for _, dep := range dependencies(a, service) {
+			unit.Add("Requires", dep+".service").Add("After", dep+".service")
+		}
+
+		c := u.Section("Container")
+		c.Add("Image", project.ParseImage(service.Image).String())
+		c.Add("ContainerName", containerName(service))
+
+		switch {
+		case service.NetworkMode != "":
+			c.Add("Network", service.NetworkMode)
+		case network != "":
+			c.Add("Network", network)
+			if containerName(service) != service.Name {
+				c.Add("PodmanArgs", "--network-alias="+Quote(service.Name))
+			}
+		}
+		if service.Hostname != "" {
+			c.Add("HostName", service.Hostname)
+		}
+		if service.User != "" {
+			c.Add("User", service.User)
+		}
+		if service.WorkingDir != "" {
+			c.Add("WorkingDir", Quote(service.WorkingDir))
+		}
+		if service.Privileged {
+			c.Add("PodmanArgs", "--privileged")
+		}
+
+		for _, port := range service.Ports {
+			c.Add("PublishPort", formatPort(port))
+		}
+
+		for _, v := range service.Volumes {
+			if v.Type == "tmpfs" {
+				c.Add("Tmpfs", v.Target)
+				continue
+			}
+
+			source := volumeSource(a, v, opt)
+			if isNamedVolume(source) {
+				volumes[source] = true
+				source += ".volume"
+			}
+			value := v.Target
+			if source != "" {
+				value = source + ":" + v.Target
+			}
+			if v.ReadOnly {
+				value += ":ro"
+			}
+			c.Add("Volume", Quote(value))
+		}
+
+		for _, name := range sortedKeys(service.Environment) {
+			if value := service.Environment[name]; value != nil {
+				c.Add("Environment", Quote(name+"="+*value))
+			}
+		}
+
+		for _, name := range sortedLabels(service.Labels) {
+			c.Add("Label", Quote(name+"="+service.Labels[name]))
+		}
+		if opt.AutoUpdate != "" {
+			c.Add("AutoUpdate", opt.AutoUpdate)
+		}
+
+		for _, cap := range service.CapAdd {
+			c.Add("AddCapability", cap)
+		}
+		for _, cap := range service.CapDrop {
+			c.Add("DropCapability", cap)
+		}
+		for _, d := range service.Devices {
+			c.Add("AddDevice", d)
+		}
+
+		if len(service.Entrypoint) > 0 {
+			c.Add("Entrypoint", service.Entrypoint[0])
+		}
+		exec := append([]string{}, service.Entrypoint...)
+		if len(exec) > 0 {
+			exec = exec[1:]
+		}
+		exec = append(exec, service.Command...)
+		if len(exec) > 0 {
+			c.Add("Exec", joinArgs(exec))
+		}
+
+		if restart := restartPolicy(service.Restart); restart != "" {
+			u.Section("Service").Add("Restart", restart)
+		}
+		u.Section("Install").Add("WantedBy", "default.target")
+
+		units = append(units, u)
+	}
+
+	names := []string{}
+	for name := range volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		u := NewUnit(name + ".volume")
+		u.Section("Volume").Add("VolumeName", name)
+		units = append(units, u)
+	}
+
+	return units
+}
+
+// Services of application in podman generate systemd --new style
+func Services(a *project.Application, opt Option) []*Unit {
+	id := a.GetID()
+	units := []*Unit{}
+
+	network := ""
+	if len(a.Services) > 1 {
+		network = id
+	}
+
+	for _, s := range a.Services {
+		service := *s
+		service.Image = project.ParseImage(s.Image).String()
+		restart := restartPolicy(service.Restart)
+		service.Restart = ""
+		if opt.AutoUpdate != "" {
+			service.Labels = types.Labels{}
+			for k, v := range s.Labels {
+				service.Labels[k] = v
+			}
+			service.Labels[autoUpdateLabel] = opt.AutoUpdate
+		}
+
+		service.Volumes = []types.ServiceVolumeConfig{}
+		for _, v := range s.Volumes {
+			v.Source = volumeSource(a, v, opt)
+			service.Volumes = append(service.Volumes, v)
+		}
+
+		args := dockerrun.ServiceArgs(&service, dockerrun.Option{
+			DataPath: opt.DataPath,
+			Network:  network,
+		})
+		args = append([]string{
+			"/usr/bin/podman", "run",
+			"--cidfile=%t/%n.ctr-id",
+			"--cgroups=no-conmon",
+			"--rm",
+			"--sdnotify=conmon",
+			"--replace",
+		}, args[2:]...)
+		// the cidfile arguments use systemd specifiers, quote the rest only
+		exec := strings.Join(args[:3], " ") + " " + joinArgs(args[3:])
+
+		u := NewUnit("container-" + unitName(a, s) + ".service")
+		unit := u.Section("Unit").
+			Add("Description", unitDescription(a, s)).
+			Add("Wants", "network-online.target").
+			Add("After", "network-online.target").
+			Add("RequiresMountsFor", "%t/containers")
+		for _, dep := range dependencies(a, s) {
+			unit.Add("Requires", "container-"+dep+".service").Add("After", "container-"+dep+".service")
+		}
+
+		svc := u.Section("Service").Add("Environment", "PODMAN_SYSTEMD_UNIT=%n")
+		if restart == "" {
+			restart = "no"
+		}
+		svc.Add("Restart", restart).Add("TimeoutStopSec", "70")
+		if network != "" {
+			svc.Add("ExecStartPre", "/usr/bin/podman network create --ignore "+Quote(network))
+		}
+		svc.Add("ExecStart", exec).
+			Add("ExecStop", "/usr/bin/podman stop --ignore -t 10 --cidfile=%t/%n.ctr-id").
+			Add("ExecStopPost", "/usr/bin/podman rm -f --ignore -t 10 --cidfile=%t/%n.ctr-id").
+			Add("Type", "notify").
+			Add("NotifyAccess", "all")
+		u.Section("Install").Add("WantedBy", "default.target")
+
+		units = append(units, u)
+	}
+
+	return units
+}
+
+func unitName(a *project.Application, service *types.ServiceConfig) string {
+	if len(a.Services) == 1 || service.Name == "" {
+		return a.GetID()
+	}
+
+	return a.GetID() + "-" + project.Slugify(service.Name)
+}
+
+func appName(a *project.Application) string {
+	if a.Name == "" {
+		return a.GetID()
+	}
+
+	return a.Name
+}
+
+func unitDescription(a *project.Application, service *types.ServiceConfig) string {
+	if len(a.Services) == 1 {
+		return appName(a)
+	}
+
+	return appName(a) + " " + service.Name
+}
+
+func containerName(service *types.ServiceConfig) string {
+	if service.ContainerName != "" {
+		return service.ContainerName
+	}
+
+	return service.Name
+}
+
+// dependencies of service in unit names
+func dependencies(a *project.Application, service *types.ServiceConfig) []string {
+	deps := []string{}
+	for _, name := range service.DependsOn {
+		for _, s := range a.Services {
+			if s.Name == name {
+				deps = append(deps, unitName(a, s))
+			}
+		}
+	}
+	sort.Strings(deps)
+
+	return deps
+}
+
+// volumeSource resolve the data path prefix, named volumes are prefixed with app id
+func volumeSource(a *project.Application, v types.ServiceVolumeConfig, opt Option) string {
+	source := project.ResolveDataPath(v.Source, opt.DataPath)
+	if isNamedVolume(source) {
+		return a.GetID() + "-" + source
+	}
+
+	return source
+}
+
+func isNamedVolume(source string) bool {
+	return source != "" && !strings.HasPrefix(source, "/") && !strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "~")
+}
+
+func restartPolicy(restart string) string {
+	switch restart {
+	case "always", "unless-stopped":
+		return "always"
+	case "on-failure":
+		return "on-failure"
+	case "no":
+		return "no"
+	}
+
+	return ""
+}
+
+func formatPort(port types.ServicePortConfig) string {
+	s := strconv.Itoa(int(port.Target))
+	if port.Published != 0 {
+		s = strconv.Itoa(int(port.Published)) + ":" + s
+	}
+	if port.Protocol != "" && port.Protocol != "tcp" {
+		s += "/" + port.Protocol
+	}
+
+	return s
+}
+
+func joinArgs(args []string) string {
+	quoted := []string{}
+	for _, arg := range args {
+		quoted = append(quoted, Quote(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func sortedKeys(m types.MappingWithEquals) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func sortedLabels(m types.Labels) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
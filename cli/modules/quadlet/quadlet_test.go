@@ -0,0 +1,111 @@
+package quadlet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"github.com/yankghjh/selfhosted_store/cli/project/projecttest"
+)
+
+func TestQuote(t *testing.T) {
+	cases := map[string]string{
+		"":               `""`,
+		"TZ=UTC":         "TZ=UTC",
+		"hello world":    `"hello world"`,
+		"100%":           `"100%%"`,
+		"$HOME":          `"$$HOME"`,
+		`say "hi"`:       `"say \"hi\""`,
+		"a\\b":           `"a\\b"`,
+		"line\nbreak":    `"line\nbreak"`,
+		"/opt/data:/cfg": "/opt/data:/cfg",
+	}
+
+	for s, expected := range cases {
+		if res := Quote(s); res != expected {
+			t.Errorf("quote %q expected %s, got %s", s, expected, res)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	app := projecttest.NewService("app", "foo/app:1.0", map[string]string{"TZ": "UTC", "MSG": "100% done"})
+	app.Restart = "unless-stopped"
+	app.DependsOn = []string{"db"}
+	app.Ports = []types.ServicePortConfig{{Target: 8080, Published: 8081}}
+	app.Volumes = []types.ServiceVolumeConfig{
+		{Source: "!data/test/config", Target: "/config"},
+		{Source: "cache", Target: "/cache"},
+		{Type: "tmpfs", Target: "/tmp"},
+	}
+	app.Command = types.ShellCommand{"serve", "--title", "My app"}
+	a := projecttest.NewApp(app, projecttest.NewService("db", "postgres", nil))
+
+	t.Run("quadlet", func(t *testing.T) { testContainers(t, a) })
+	t.Run("systemd", func(t *testing.T) { testServices(t, a) })
+}
+
+func testContainers(t *testing.T, a *project.Application) {
+	units := Convert(a, DefaultOption())
+
+	files := []string{}
+	contents := map[string]string{}
+	for _, u := range units {
+		files = append(files, u.Filename)
+		contents[u.Filename] = u.String()
+	}
+	expected := "test.network,test-app.container,test-db.container,test-cache.volume"
+	if strings.Join(files, ",") != expected {
+		t.Errorf("expected units %s, got %s", expected, strings.Join(files, ","))
+	}
+
+	for _, line := range []string{
+		"Requires=test-db.service",
+		"After=test-db.service",
+		"Image=docker.io/foo/app:1.0",
+		"ContainerName=app",
+		"Network=test.network",
+		"PublishPort=8081:8080",
+		"Volume=/opt/appdata/test/config:/config",
+		"Volume=test-cache.volume:/cache",
+		"Tmpfs=/tmp",
+		`Environment="MSG=100%% done"`,
+		"Environment=TZ=UTC",
+		"AutoUpdate=registry",
+		`Exec=serve --title "My app"`,
+		"Restart=always",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(contents["test-app.container"], line+"\n") {
+			t.Errorf("%s is missing:\n%s", line, contents["test-app.container"])
+		}
+	}
+	if !strings.Contains(contents["test-cache.volume"], "VolumeName=test-cache\n") {
+		t.Errorf("volume name is missing:\n%s", contents["test-cache.volume"])
+	}
+}
+
+func testServices(t *testing.T, a *project.Application) {
+	opt := DefaultOption()
+	opt.Mode = ModeSystemd
+	units := Convert(a, opt)
+
+	if len(units) != 2 || units[0].Filename != "container-test-app.service" {
+		t.Fatalf("unexpected units %+v", units)
+	}
+
+	content := units[0].String()
+	for _, line := range []string{
+		"Requires=container-test-db.service",
+		"ExecStartPre=/usr/bin/podman network create --ignore test",
+		"ExecStart=/usr/bin/podman run --cidfile=%t/%n.ctr-id --cgroups=no-conmon --rm --sdnotify=conmon --replace -d --name app --network test --network-alias app -p 8081:8080 -v /opt/appdata/test/config:/config -v test-cache:/cache --tmpfs /tmp",
+		`"MSG=100%% done"`,
+		"-l io.containers.autoupdate=registry docker.io/foo/app:1.0 serve --title \"My app\"",
+		"Restart=always",
+	} {
+		if !strings.Contains(content, line) {
+			t.Errorf("%s is missing:\n%s", line, content)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package quadlet
+
+import (
+	"regexp"
+	"strings"
+)
+
+var safeValuePattern = regexp.MustCompile(`^[^\s"'\\%$;]+$`)
+
+// Unit is a systemd unit file
+type Unit struct {
+	Filename string
+	Sections []*Section
+}
+
+// Section of unit file
+type Section struct {
+	Name    string
+	Entries [][2]string
+}
+
+// NewUnit with file name
+func NewUnit(filename string) *Unit {
+	return &Unit{Filename: filename, Sections: []*Section{}}
+}
+
+// Section of unit by name, created when not exist
+func (u *Unit) Section(name string) *Section {
+	for _, s := range u.Sections {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	s := &Section{Name: name, Entries: [][2]string{}}
+	u.Sections = append(u.Sections, s)
+	return s
+}
+
+// Add an entry to section
+func (s *Section) Add(key, value string) *Section {
+	s.Entries = append(s.Entries, [2]string{key, value})
+	return s
+}
+
+// String of unit file content
+func (u *Unit) String() string {
+	blocks := []string{}
+	for _, s := range u.Sections {
+		lines := []string{"[" + s.Name + "]"}
+		for _, e := range s.Entries {
+			lines = append(lines, e[0]+"="+e[1])
+		}
+		blocks = append(blocks, strings.Join(lines, "\n")+"\n")
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+// Escape specifiers and variables of systemd in value
+func Escape(s string) string {
+	return strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+}
+
+// Quote value for systemd command lines and lists
+func Quote(s string) string {
+	if s != "" && safeValuePattern.MatchString(s) {
+		return s
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(Escape(s)) + `"`
+}
@@ -0,0 +1,272 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// IndexVersion of the index format, bumped on incompatible changes
+const IndexVersion = 2
+
+// Fields of application in index, with the weight of term score
+var Fields = map[string]int{
+	"name":        10,
+	"category":    5,
+	"image":       3,
+	"description": 1,
+}
+
+// StemRule strips Suffix and appends Replace when the word is longer than Min characters
+type StemRule struct {
+	Suffix  string `json:"suffix"`
+	Replace string `json:"replace"`
+	Min     int    `json:"min"`
+}
+
+// StemRules of the light english stemmer, the first matched rule is applied
+var StemRules = []StemRule{
+	{Suffix: "sses", Replace: "ss", Min: 5},
+	{Suffix: "ies", Replace: "y", Min: 4},
+	{Suffix: "ss", Replace: "ss", Min: 2},
+	{Suffix: "s", Replace: "", Min: 3},
+	{Suffix: "ing", Replace: "", Min: 5},
+	{Suffix: "ed", Replace: "", Min: 4},
+	{Suffix: "ly", Replace: "", Min: 4},
+}
+
+// Index is the prebuilt inverted index,
+// Terms maps a stemmed term to flat pairs of document index and score,
+// Words maps the unstemmed words differing from their stems the same way,
+// they are only matched by prefix
+type Index struct {
+	Version int              `json:"version"`
+	Fields  map[string]int   `json:"fields"`
+	Docs    []*Doc           `json:"docs"`
+	Terms   map[string][]int `json:"terms"`
+	Words   map[string][]int `json:"words"`
+}
+
+// Doc is the search result of an application
+type Doc struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Icon        string   `json:"icon,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Categories  []string `json:"categories,omitempty"`
+}
+
+// Result of query
+type Result struct {
+	Doc   *Doc `json:"doc"`
+	Score int  `json:"score"`
+}
+
+// Stem word by the stem rules
+func Stem(word string) string {
+	for _, r := range StemRules {
+		if utf8.RuneCountInString(word) > r.Min && strings.HasSuffix(word, r.Suffix) {
+			return strings.TrimSuffix(word, r.Suffix) + r.Replace
+		}
+	}
+
+	return word
+}
+
+// Tokenize text to stemmed terms
+func Tokenize(text string) []string {
+	terms := Words(text)
+	for i, word := range terms {
+		terms[i] = Stem(word)
+	}
+
+	return terms
+}
+
+// Words of text, split by non letters and digits,
+// han characters are split to bigrams
+func Words(text string) []string {
+	terms := []string{}
+
+	word := []rune{}
+	han := []rune{}
+	flush := func() {
+		if len(word) > 0 {
+			terms = append(terms, string(word))
+			word = word[:0]
+		}
+		if len(han) == 1 {
+			terms = append(terms, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			terms = append(terms, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if len(word) > 0 {
+				flush()
+			}
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if len(han) > 0 {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return terms
+}
+
+// Build index of applications, url is the detail page of app with {id} placeholder
+func Build(apps []*project.Application, url string) *Index {
+	index := &Index{
+		Version: IndexVersion,
+		Fields:  Fields,
+		Docs:    []*Doc{},
+		Terms:   map[string][]int{},
+		Words:   map[string][]int{},
+	}
+
+	for i, a := range apps {
+		doc := &Doc{
+			ID:          a.GetID(),
+			Name:        a.Name,
+			Description: a.Description,
+			Icon:        a.Icon,
+			Categories:  a.Category,
+		}
+		if url != "" {
+			doc.URL = strings.Replace(url, "{id}", doc.ID, -1)
+		}
+		index.Docs = append(index.Docs, doc)
+
+		images := []string{}
+		for _, service := range a.Services {
+			images = append(images, project.ParseImage(service.Image).Repository)
+		}
+
+		scores := map[string]int{}
+		words := map[string]int{}
+		for field, text := range map[string]string{
+			"name":        a.Name,
+			"category":    strings.Join(a.Category, " "),
+			"image":       strings.Join(images, " "),
+			"description": a.Description,
+		} {
+			for _, word := range Words(text) {
+				term := Stem(word)
+				scores[term] += Fields[field]
+				if word != term {
+					words[word] += Fields[field]
+				}
+			}
+		}
+
+		for term, score := range scores {
+			index.Terms[term] = append(index.Terms[term], i, score)
+		}
+		for word, score := range words {
+			index.Words[word] = append(index.Words[word], i, score)
+		}
+	}
+
+	return index
+}
+
+// Query index, every query term should match, the last term matches as prefix
+// before stemming, so a partly typed word keeps its results
+func (index *Index) Query(q string, limit int) []*Result {
+	words := Words(q)
+	if len(words) == 0 {
+		return []*Result{}
+	}
+
+	keys := []string{}
+	for term := range index.Terms {
+		keys = append(keys, term)
+	}
+	for word := range index.Words {
+		if _, ok := index.Terms[word]; !ok {
+			keys = append(keys, word)
+		}
+	}
+	sort.Strings(keys)
+
+	scores := map[int]int{}
+	for i, word := range words {
+		term := Stem(word)
+		matched := map[int]int{}
+		for doc, score := range pairs(index.Terms[term]) {
+			matched[doc] += score
+		}
+
+		if i == len(words)-1 {
+			// documents without exact match score half of the best prefix match
+			prefixed := map[int]int{}
+			start := sort.SearchStrings(keys, word)
+			for _, key := range keys[start:] {
+				if !strings.HasPrefix(key, word) {
+					break
+				}
+				for _, flat := range [][]int{index.Terms[key], index.Words[key]} {
+					for doc, score := range pairs(flat) {
+						if score = (score + 1) / 2; score > prefixed[doc] {
+							prefixed[doc] = score
+						}
+					}
+				}
+			}
+			for doc, score := range prefixed {
+				if _, ok := matched[doc]; !ok {
+					matched[doc] = score
+				}
+			}
+		}
+
+		next := map[int]int{}
+		for doc, score := range matched {
+			if _, ok := scores[doc]; ok || i == 0 {
+				next[doc] = scores[doc] + score
+			}
+		}
+		scores = next
+	}
+
+	results := []*Result{}
+	for doc, score := range scores {
+		results = append(results, &Result{Doc: index.Docs[doc], Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Doc.Name < results[j].Doc.Name
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+func pairs(flat []int) map[int]int {
+	m := map[int]int{}
+	for i := 0; i+1 < len(flat); i += 2 {
+		m[flat[i]] += flat[i+1]
+	}
+
+	return m
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"classes", "class"},
+		{"libraries", "library"},
+		{"glass", "glass"},
+		{"apps", "app"},
+		{"bus", "bus"},
+		{"streaming", "stream"},
+		{"sing", "sing"},
+		{"hosted", "host"},
+		{"red", "red"},
+		{"weekly", "week"},
+		// multibyte words are measured in characters
+		{"éés", "éés"},
+		{"ññing", "ññing"},
+		{"cafés", "café"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"Media Servers", []string{"media", "server"}},
+		{"home-assistant v2", []string{"home", "assistant", "v2"}},
+		{"下载", []string{"下载"}},
+		{"媒体服务器", []string{"媒体", "体服", "服务", "务器"}},
+		{"家", []string{"家"}},
+		{"qBittorrent下载器", []string{"qbittorrent", "下载", "载器"}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	apps := []*project.Application{}
+	for _, app := range []struct {
+		name, description, category, image string
+	}{
+		{"Jellyfin", "The free software media system", "Media Servers", "jellyfin/jellyfin"},
+		{"Plex", "Streaming movies and shows", "Media Servers", "plexinc/pms-docker"},
+		{"Nextcloud", "File hosting, sharing and sync", "Cloud", "nextcloud"},
+	} {
+		a := project.NewApplication()
+		a.Name = app.name
+		a.Description = app.description
+		a.Category = []string{app.category}
+		a.Services = append(a.Services, &types.ServiceConfig{Name: "app", Image: app.image})
+		apps = append(apps, a)
+	}
+	index := Build(apps, "app/{id}.html")
+
+	tests := []struct {
+		q     string
+		limit int
+		want  []string
+	}{
+		{"", 0, []string{}},
+		{"jellyfin", 0, []string{"Jellyfin"}},
+		{"media", 0, []string{"Jellyfin", "Plex"}},
+		{"media", 1, []string{"Jellyfin"}},
+		{"media stre", 0, []string{"Plex"}},
+		{"media streami", 0, []string{"Plex"}},
+		{"next", 0, []string{"Nextcloud"}},
+		{"hosting", 0, []string{"Nextcloud"}},
+		{"media cloud", 0, []string{}},
+		// typing letter by letter keeps the results
+		{"s", 0, []string{"Jellyfin", "Plex", "Nextcloud"}},
+		{"stream", 0, []string{"Plex"}},
+		{"streami", 0, []string{"Plex"}},
+		{"streamin", 0, []string{"Plex"}},
+		{"streaming", 0, []string{"Plex"}},
+		{"shari", 0, []string{"Nextcloud"}},
+		{"sharing", 0, []string{"Nextcloud"}},
+	}
+	for _, tt := range tests {
+		names := []string{}
+		for _, r := range index.Query(tt.q, tt.limit) {
+			names = append(names, r.Doc.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("Query(%q, %d) = %v, want %v", tt.q, tt.limit, names, tt.want)
+		}
+	}
+
+	if r := index.Query("nextcloud", 0); len(r) != 1 || r[0].Doc.URL != "app/nextcloud.html" {
+		t.Errorf("Query(nextcloud) url = %+v", r)
+	}
+}
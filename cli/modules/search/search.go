@@ -0,0 +1,104 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("search", Generater)
+}
+
+// Contract of the index and query, for front ends implementing the search
+type Contract struct {
+	Version   int            `json:"version"`
+	Index     string         `json:"index"`
+	Tokenizer Tokenizer      `json:"tokenizer"`
+	Fields    map[string]int `json:"fields"`
+	Query     QueryContract  `json:"query"`
+}
+
+// Tokenizer of the index, queries must be tokenized the same way
+type Tokenizer struct {
+	Lowercase bool       `json:"lowercase"`
+	Split     string     `json:"split"`
+	Han       string     `json:"han"`
+	Stem      []StemRule `json:"stem"`
+}
+
+// QueryContract describe how to query the index
+type QueryContract struct {
+	Match   string   `json:"match"`
+	Prefix  string   `json:"prefix"`
+	Score   string   `json:"score"`
+	Request Request  `json:"request"`
+	Results []Result `json:"results"`
+}
+
+// Request of query
+type Request struct {
+	Q     string `json:"q"`
+	Limit int    `json:"limit"`
+}
+
+// Generater search index and its contract
+//
+//	url: app/{id}.html
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("url", "app/{id}.html")
+	index := Build(apps, o.Config.GetString("url"))
+
+	contract := &Contract{
+		Version: IndexVersion,
+		Index:   "index.json",
+		Tokenizer: Tokenizer{
+			Lowercase: true,
+			Split:     "runs of unicode letters and digits",
+			Han:       "bigrams, a single character is kept as is",
+			Stem:      StemRules,
+		},
+		Fields: Fields,
+		Query: QueryContract{
+			Match:   "every query term must match a document",
+			Prefix:  "the last query word is not stemmed and also matches terms and words starting with it, documents without an exact match of its stem score the best prefix match with half score rounded up",
+			Score:   "sum of the field weights of matched terms, terms and words map to pairs of doc index and score, words are the unstemmed forms and only match by prefix",
+			Request: Request{Q: "media server", Limit: 20},
+			Results: []Result{{Doc: &Doc{ID: "plex", Name: "Plex"}, Score: 21}},
+		},
+	}
+
+	path := o.Project.GetDistPath("search")
+	os.MkdirAll(path, os.ModePerm)
+
+	for name, v := range map[string]interface{}{
+		"index.json":    index,
+		"contract.json": contract,
+	} {
+		var res []byte
+		if name == "index.json" {
+			res, err = json.Marshal(v)
+		} else {
+			res, err = json.MarshalIndent(v, "", "  ")
+		}
+		if err != nil {
+			return fmt.Errorf("marshal search %s error: %s", name, err.Error())
+		}
+
+		filename := path + "/" + name
+		err = ioutil.WriteFile(filename, res, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write search file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,385 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	compose "github.com/yankghjh/selfhosted_store/cli/modules/docker-compose"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("site", Generater)
+}
+
+// defaultCategory of apps without category
+var defaultCategory = "Other"
+
+// defaultTemplates of the store, name to path in dist
+var defaultTemplates = map[string]string{
+	"portainer": "templates/portainer/template.json",
+	"yacht":     "templates/yacht/yacht.json",
+}
+
+// Site is the store wide data of pages
+type Site struct {
+	Title      string
+	Categories []*Category
+	Templates  []*Template
+}
+
+// Category of apps
+type Category struct {
+	Name string
+	Slug string
+	Apps []*App
+}
+
+// Template address of the store
+type Template struct {
+	Name string
+	URL  string
+}
+
+// App is an application with the data rendered in detail page
+type App struct {
+	*project.Application
+	Slug        string
+	Initial     string
+	Categories  []*Category
+	Ports       []*Item
+	Volumes     []*Item
+	Environment []*Item
+	Devices     []*Item
+	Labels      []*Item
+	Compose     string
+	// WebUIURL with the IP placeholder of host
+	WebUIURL string
+}
+
+// Item of ports, volumes, environment, devices and labels
+type Item struct {
+	Target      string
+	Value       string
+	Description string
+}
+
+// Page data of templates, Root is the relative path to site root
+type Page struct {
+	Site     *Site
+	Root     string
+	Apps     []*App
+	Category *Category
+	App      *App
+}
+
+// Generater static store website
+//
+//	title: Selfhosted Store
+//	url: https://example.com/store/
+//	theme: path/to/theme
+//	templates:
+//	  portainer: templates/portainer/template.json
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("title", "Selfhosted Store")
+	o.Config.SetDefault("templates", defaultTemplates)
+	baseURL := o.Config.GetString("url")
+	if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	pages, err := LoadTheme(o.Config.GetString("theme"))
+	if err != nil {
+		return err
+	}
+
+	site := &Site{
+		Title:      o.Config.GetString("title"),
+		Categories: []*Category{},
+		Templates:  []*Template{},
+	}
+
+	templates := o.Config.GetStringMapString("templates")
+	names := []string{}
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		site.Templates = append(site.Templates, &Template{Name: name, URL: baseURL + templates[name]})
+	}
+
+	list := []*App{}
+	for _, a := range apps {
+		app, err := NewApp(a)
+		if err != nil {
+			return err
+		}
+		list = append(list, app)
+	}
+	site.Categories = Categories(list)
+
+	files := map[string]*bytes.Buffer{}
+	render := func(filename, name string, page *Page) error {
+		page.Site = site
+		page.Root = strings.Repeat("../", strings.Count(filename, "/"))
+		buf := &bytes.Buffer{}
+		if err := pages[name].ExecuteTemplate(buf, "layout", page); err != nil {
+			return fmt.Errorf("render page %s error: %s", filename, err.Error())
+		}
+		files[filename] = buf
+		return nil
+	}
+
+	if err := render("index.html", "index.html", &Page{Apps: list}); err != nil {
+		return err
+	}
+	for _, c := range site.Categories {
+		if err := render("category/"+c.Slug+".html", "category.html", &Page{Apps: c.Apps, Category: c}); err != nil {
+			return err
+		}
+	}
+	for _, app := range list {
+		if err := render("app/"+app.Slug+".html", "app.html", &Page{App: app}); err != nil {
+			return err
+		}
+	}
+	files["assets/style.css"] = bytes.NewBufferString(styleSheet)
+	if style, err := ioutil.ReadFile(filepath.Join(o.Config.GetString("theme"), "style.css")); err == nil {
+		files["assets/style.css"] = bytes.NewBuffer(style)
+	}
+
+	for name, buf := range files {
+		filename := o.Project.GetDistPath(name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		err := ioutil.WriteFile(filename, buf.Bytes(), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write page file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// LoadTheme templates of pages, files in theme path replace the default ones
+func LoadTheme(theme string) (map[string]*template.Template, error) {
+	sources := map[string]string{
+		"layout.html":   layoutTemplate,
+		"index.html":    indexTemplate,
+		"category.html": categoryTemplate,
+		"app.html":      appTemplate,
+	}
+
+	if theme != "" {
+		for name := range sources {
+			content, err := ioutil.ReadFile(filepath.Join(theme, name))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, fmt.Errorf("read theme file %s error: %s", name, err.Error())
+			}
+			sources[name] = string(content)
+		}
+	}
+
+	pages := map[string]*template.Template{}
+	for _, name := range []string{"index.html", "category.html", "app.html"} {
+		t, err := template.New(name).Parse(sources["layout.html"])
+		if err != nil {
+			return nil, fmt.Errorf("parse template layout.html error: %s", err.Error())
+		}
+		if _, err := t.Parse(sources[name]); err != nil {
+			return nil, fmt.Errorf("parse template %s error: %s", name, err.Error())
+		}
+		pages[name] = t
+	}
+
+	return pages, nil
+}
+
+// Categories of apps sorted by name, names with the same slug are one category
+// named by its first app
+func Categories(apps []*App) []*Category {
+	categories := []*Category{}
+	index := map[string]*Category{}
+	for _, app := range apps {
+		names := app.Category
+		if len(names) == 0 {
+			names = []string{defaultCategory}
+		}
+		for _, name := range names {
+			key := project.Slugify(name)
+			if key == "" {
+				key = name
+			}
+			c, ok := index[key]
+			if !ok {
+				c = &Category{Name: name, Slug: project.Slugify(name), Apps: []*App{}}
+				if c.Slug == "" {
+					c.Slug = "category-" + strconv.Itoa(len(index)+1)
+				}
+				index[key] = c
+				categories = append(categories, c)
+			}
+			if len(c.Apps) > 0 && c.Apps[len(c.Apps)-1] == app {
+				continue
+			}
+			c.Apps = append(c.Apps, app)
+			app.Categories = append(app.Categories, c)
+		}
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories
+}
+
+// NewApp of detail page
+func NewApp(a *project.Application) (*App, error) {
+	app := &App{
+		Application: a,
+		Slug:        a.GetID(),
+		Categories:  []*Category{},
+		Ports:       []*Item{},
+		Volumes:     []*Item{},
+		Environment: []*Item{},
+		Devices:     []*Item{},
+		Labels:      []*Item{},
+		WebUIURL:    a.GetWebUIURL("IP"),
+	}
+	if name := []rune(a.Name); len(name) > 0 {
+		app.Initial = strings.ToUpper(string(name[0]))
+	}
+
+	for _, service := range a.Services {
+		for _, port := range service.Ports {
+			target := strconv.Itoa(int(port.Target))
+			item := &Item{Target: target}
+			if port.Protocol != "" && port.Protocol != "tcp" {
+				item.Target += "/" + port.Protocol
+			}
+			if port.Published != 0 {
+				item.Value = strconv.Itoa(int(port.Published))
+			}
+			item.Description = describe(a, project.ParameterTypePort, target)
+			app.Ports = append(app.Ports, item)
+		}
+
+		for _, v := range service.Volumes {
+			app.Volumes = append(app.Volumes, &Item{
+				Target:      v.Target,
+				Value:       v.Source,
+				Description: describe(a, project.ParameterTypePath, v.Target),
+			})
+		}
+
+		names := []string{}
+		for name := range service.Environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			item := &Item{Target: name, Description: describe(a, project.ParameterTypeVariable, name)}
+			if value := service.Environment[name]; value != nil {
+				item.Value = *value
+			}
+			if isSecret(a, name) {
+				item.Value = ""
+			}
+			app.Environment = append(app.Environment, item)
+		}
+
+		// device parameters target the host path
+		for _, device := range service.Devices {
+			parts := strings.SplitN(device, ":", 3)
+			item := &Item{Target: parts[0], Value: parts[0], Description: describe(a, project.ParameterTypeDevice, parts[0])}
+			if len(parts) > 1 {
+				item.Target = parts[1]
+			}
+			app.Devices = append(app.Devices, item)
+		}
+
+		labels := []string{}
+		for name := range service.Labels {
+			labels = append(labels, name)
+		}
+		sort.Strings(labels)
+		for _, name := range labels {
+			app.Labels = append(app.Labels, &Item{
+				Target:      name,
+				Value:       service.Labels[name],
+				Description: describe(a, project.ParameterTypeLabel, name),
+			})
+		}
+	}
+
+	if len(a.Services) > 0 {
+		res, err := compose.Encoder(maskSecrets(a))
+		if err != nil {
+			return nil, fmt.Errorf("encode compose file of %s error: %s", a.Name, err.Error())
+		}
+		app.Compose = string(res)
+	}
+
+	return app, nil
+}
+
+// maskSecrets of a copy of application, values of secret environment are blanked
+func maskSecrets(a *project.Application) *project.Application {
+	masked := *a
+	masked.Services = []*types.ServiceConfig{}
+
+	for _, s := range a.Services {
+		service := *s
+		service.Environment = types.MappingWithEquals{}
+		for name, value := range s.Environment {
+			if value != nil && isSecret(a, name) {
+				empty := ""
+				value = &empty
+			}
+			service.Environment[name] = value
+		}
+		masked.Services = append(masked.Services, &service)
+	}
+
+	return &masked
+}
+
+func isSecret(a *project.Application, name string) bool {
+	if a.GetParameter(project.ParameterTypeSecret, name) != nil {
+		return true
+	}
+	p := a.GetParameter(project.ParameterTypeVariable, name)
+
+	return p != nil && p.IsSecret()
+}
+
+func describe(a *project.Application, t, target string) string {
+	p := a.GetParameter(t, target)
+	if p == nil && t == project.ParameterTypeVariable {
+		p = a.GetParameter(project.ParameterTypeSecret, target)
+	}
+	if p == nil {
+		return ""
+	}
+	if p.Description != "" {
+		return p.Description
+	}
+
+	return p.GetLabel()
+}
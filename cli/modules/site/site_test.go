@@ -0,0 +1,116 @@
+package site
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestNewAppMasksSecrets(t *testing.T) {
+	password := "hunter2"
+	tz := "UTC"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.WebUI = &project.WebUI{Scheme: "http", Port: 8080, Path: "/"}
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test",
+		Environment: types.MappingWithEquals{"PASSWORD": &password, "TZ": &tz},
+		Ports:       []types.ServicePortConfig{{Target: 8080, Published: 8081}},
+	})
+	a.Parameters = append(a.Parameters, &project.Parameter{Type: project.ParameterTypeSecret, Target: "PASSWORD"})
+
+	app, err := NewApp(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(app.Compose, password) {
+		t.Errorf("compose contains the secret value:\n%s", app.Compose)
+	}
+	if !strings.Contains(app.Compose, "TZ: UTC") {
+		t.Errorf("compose lost the plain variable:\n%s", app.Compose)
+	}
+	for _, item := range app.Environment {
+		if item.Value == password {
+			t.Errorf("environment table contains the secret value")
+		}
+	}
+	if *a.Services[0].Environment["PASSWORD"] != password {
+		t.Errorf("the application is modified")
+	}
+	if app.WebUIURL != "http://IP:8081/" {
+		t.Errorf("WebUIURL = %q, want http://IP:8081/", app.WebUIURL)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	apps := []*App{}
+	for _, categories := range [][]string{{"Media"}, {"media", "Tools"}, {}, {"Media", "media"}} {
+		a := project.NewApplication()
+		a.Name = "Test"
+		a.Category = categories
+		apps = append(apps, &App{Application: a, Categories: []*Category{}})
+	}
+
+	res := Categories(apps)
+
+	expected := []struct {
+		name string
+		slug string
+		apps int
+	}{
+		{"Media", "media", 3},
+		{"Other", "other", 1},
+		{"Tools", "tools", 1},
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("categories = %+v, want %+v", res, expected)
+	}
+	for i, c := range expected {
+		if res[i].Name != c.name || res[i].Slug != c.slug || len(res[i].Apps) != c.apps {
+			t.Errorf("category %d = %s %s %d apps, want %s %s %d apps", i, res[i].Name, res[i].Slug, len(res[i].Apps), c.name, c.slug, c.apps)
+		}
+	}
+	if len(apps[3].Categories) != 1 {
+		t.Errorf("app is listed %d times in the same category", len(apps[3].Categories))
+	}
+}
+
+func TestNewAppDevicesAndLabels(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:    "test",
+		Image:   "foo/test",
+		Devices: []string{"/dev/dri:/dev/dri", "/dev/ttyUSB0:/dev/zigbee:rwm"},
+		Labels:  types.Labels{"traefik.enable": "true"},
+	})
+	a.Parameters = append(a.Parameters,
+		&project.Parameter{Type: project.ParameterTypeDevice, Target: "/dev/dri", Name: "GPU"},
+		&project.Parameter{Type: project.ParameterTypeLabel, Target: "traefik.enable", Description: "Expose by traefik"},
+	)
+
+	app, err := NewApp(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	devices := []Item{
+		{Target: "/dev/dri", Value: "/dev/dri", Description: "GPU"},
+		{Target: "/dev/zigbee", Value: "/dev/ttyUSB0"},
+	}
+	if len(app.Devices) != len(devices) {
+		t.Fatalf("devices = %+v, want %+v", app.Devices, devices)
+	}
+	for i, d := range devices {
+		if *app.Devices[i] != d {
+			t.Errorf("device %d = %+v, want %+v", i, *app.Devices[i], d)
+		}
+	}
+	if len(app.Labels) != 1 || *app.Labels[0] != (Item{Target: "traefik.enable", Value: "true", Description: "Expose by traefik"}) {
+		t.Errorf("labels = %+v", app.Labels)
+	}
+}
@@ -0,0 +1,169 @@
+package site
+
+// default theme, every file can be replaced by the same name in theme path
+
+var layoutTemplate = `{{define "layout"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{template "title" .}} - {{.Site.Title}}</title>
+  <link rel="stylesheet" href="{{.Root}}assets/style.css">
+</head>
+<body>
+  <header>
+    <a class="brand" href="{{.Root}}index.html">{{.Site.Title}}</a>
+    <nav>
+      {{- range .Site.Categories}}
+      <a href="{{$.Root}}category/{{.Slug}}.html">{{.Name}}</a>
+      {{- end}}
+    </nav>
+  </header>
+  <main>
+    {{template "content" .}}
+  </main>
+  <footer>
+    Generated by <a href="https://github.com/yankghjh/selfhosted_store">selfhosted_store</a>
+  </footer>
+</body>
+</html>
+{{end}}
+
+{{define "icon"}}
+{{- if .Icon}}<img class="icon" src="{{.Icon}}" alt="{{.Name}}">
+{{- else}}<span class="icon placeholder">{{.Initial}}</span>{{end}}
+{{- end}}
+
+{{define "cards"}}
+<ul class="cards">
+  {{- range .Apps}}
+  <li>
+    <a href="{{$.Root}}app/{{.Slug}}.html">
+      {{template "icon" .}}
+      <strong>{{.Name}}</strong>
+      <p>{{.Description}}</p>
+    </a>
+  </li>
+  {{- end}}
+</ul>
+{{end}}
+`
+
+var indexTemplate = `{{define "title"}}Apps{{end}}
+
+{{define "content"}}
+<h1>{{.Site.Title}}</h1>
+<p>{{len .Apps}} apps in {{len .Site.Categories}} categories.</p>
+{{template "cards" .}}
+{{end}}
+`
+
+var categoryTemplate = `{{define "title"}}{{.Category.Name}}{{end}}
+
+{{define "content"}}
+<h1>{{.Category.Name}}</h1>
+{{template "cards" .}}
+{{end}}
+`
+
+var appTemplate = `{{define "title"}}{{.App.Name}}{{end}}
+
+{{define "content"}}
+{{with .App}}
+<section class="app">
+  {{template "icon" .}}
+  <div>
+    <h1>{{.Name}}</h1>
+    <p>{{.Description}}</p>
+    <p class="categories">
+      {{- range .Categories}}
+      <a href="{{$.Root}}category/{{.Slug}}.html">{{.Name}}</a>
+      {{- end}}
+    </p>
+    {{- with .WebUIURL}}
+    <p class="webui">Web UI: <code>{{.}}</code></p>
+    {{- end}}
+    {{- range .Links}}
+    <a class="link" href="{{.URL}}">{{.Type}}</a>
+    {{- end}}
+  </div>
+</section>
+
+{{with .Overview}}<h2>Overview</h2>
+<p class="overview">{{.}}</p>{{end}}
+
+{{with .Ports}}<h2>Ports</h2>
+<table>
+  <tr><th>Container</th><th>Host</th><th>Description</th></tr>
+  {{- range .}}
+  <tr><td>{{.Target}}</td><td>{{.Value}}</td><td>{{.Description}}</td></tr>
+  {{- end}}
+</table>{{end}}
+
+{{with .Volumes}}<h2>Volumes</h2>
+<table>
+  <tr><th>Container</th><th>Host</th><th>Description</th></tr>
+  {{- range .}}
+  <tr><td>{{.Target}}</td><td>{{.Value}}</td><td>{{.Description}}</td></tr>
+  {{- end}}
+</table>{{end}}
+
+{{with .Environment}}<h2>Environment</h2>
+<table>
+  <tr><th>Name</th><th>Default</th><th>Description</th></tr>
+  {{- range .}}
+  <tr><td>{{.Target}}</td><td>{{.Value}}</td><td>{{.Description}}</td></tr>
+  {{- end}}
+</table>{{end}}
+
+{{with .Devices}}<h2>Devices</h2>
+<table>
+  <tr><th>Container</th><th>Host</th><th>Description</th></tr>
+  {{- range .}}
+  <tr><td>{{.Target}}</td><td>{{.Value}}</td><td>{{.Description}}</td></tr>
+  {{- end}}
+</table>{{end}}
+
+{{with .Labels}}<h2>Labels</h2>
+<table>
+  <tr><th>Name</th><th>Value</th><th>Description</th></tr>
+  {{- range .}}
+  <tr><td>{{.Target}}</td><td>{{.Value}}</td><td>{{.Description}}</td></tr>
+  {{- end}}
+</table>{{end}}
+
+<h2>Docker Compose</h2>
+<pre><code>{{.Compose}}</code></pre>
+
+{{with $.Site.Templates}}<h2>Templates</h2>
+<ul class="templates">
+  {{- range .}}
+  <li>{{.Name}}: <code>{{.URL}}</code></li>
+  {{- end}}
+</ul>{{end}}
+{{end}}
+{{end}}
+`
+
+var styleSheet = `* { box-sizing: border-box; }
+body { margin: 0; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292e; background: #f6f8fa; }
+a { color: #0366d6; text-decoration: none; }
+header { display: flex; flex-wrap: wrap; align-items: center; gap: 1em; padding: 1em 2em; background: #24292e; }
+header a { color: #fff; }
+header .brand { font-weight: bold; font-size: 1.2em; }
+header nav { display: flex; flex-wrap: wrap; gap: 1em; }
+main { max-width: 1100px; margin: 0 auto; padding: 1em 2em; }
+footer { text-align: center; padding: 2em; color: #586069; }
+.cards { list-style: none; padding: 0; display: grid; grid-template-columns: repeat(auto-fill, minmax(240px, 1fr)); gap: 1em; }
+.cards a { display: block; height: 100%; padding: 1em; background: #fff; border: 1px solid #e1e4e8; border-radius: 6px; color: inherit; }
+.cards p { color: #586069; font-size: .9em; }
+.icon { width: 48px; height: 48px; border-radius: 8px; float: left; margin-right: .8em; }
+.placeholder { display: inline-flex; align-items: center; justify-content: center; background: #0366d6; color: #fff; font-size: 1.5em; }
+.app { display: flex; align-items: flex-start; }
+.app .icon { width: 96px; height: 96px; }
+.categories a, .link { display: inline-block; margin-right: .5em; padding: .1em .6em; border: 1px solid #e1e4e8; border-radius: 1em; background: #fff; font-size: .9em; }
+.overview { white-space: pre-line; }
+table { width: 100%; border-collapse: collapse; background: #fff; }
+th, td { text-align: left; padding: .5em; border: 1px solid #e1e4e8; }
+pre { padding: 1em; overflow: auto; background: #fff; border: 1px solid #e1e4e8; }
+`
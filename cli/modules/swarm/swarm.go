@@ -0,0 +1,269 @@
+package swarm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	units "github.com/docker/go-units"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterEncoder("swarm", Encoder)
+	project.RegisterGenerater("swarm", Generater)
+}
+
+// Option for swarm stacks
+type Option struct {
+	DataPath string
+	// PortMode is ingress or host
+	PortMode string
+	// SecretFile replaces secret environment with NAME_FILE for images
+	// implement the _FILE convention, otherwise the variable is dropped and
+	// the image has to read the mounted secret itself
+	SecretFile bool
+}
+
+// DefaultOption of swarm stacks
+func DefaultOption() Option {
+	return Option{
+		DataPath:   "/opt/appdata",
+		PortMode:   "ingress",
+		SecretFile: true,
+	}
+}
+
+// Encoder for swarm stack file
+func Encoder(a *project.Application) ([]byte, error) {
+	cfg, err := Convert(a, DefaultOption())
+	if err != nil {
+		return nil, err
+	}
+
+	return Marshal(cfg)
+}
+
+// Marshal stack file, the external secrets to create are listed in the header
+func Marshal(cfg *types.Config) ([]byte, error) {
+	res, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Secrets) == 0 {
+		return res, nil
+	}
+
+	names := []string{}
+	for name := range cfg.Secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := "# create the secrets before docker stack deploy:\n"
+	for _, name := range names {
+		header += "#   printf '%s' 'value' | docker secret create " + name + " -\n"
+	}
+
+	return append([]byte(header), res...), nil
+}
+
+// Generater swarm stack files, one stack file per app
+//
+//	data_path: /opt/appdata
+//	port_mode: ingress
+//	secret_file: true
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	opt := DefaultOption()
+	if v := o.Config.GetString("data_path"); v != "" {
+		opt.DataPath = strings.TrimSuffix(v, "/")
+	}
+	if v := o.Config.GetString("port_mode"); v != "" {
+		opt.PortMode = v
+	}
+	o.Config.SetDefault("secret_file", opt.SecretFile)
+	opt.SecretFile = o.Config.GetBool("secret_file")
+
+	path := o.Project.GetDistPath("templates", "swarm")
+	os.MkdirAll(path, os.ModePerm)
+
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		cfg, err := Convert(a, opt)
+		if err != nil {
+			return fmt.Errorf("convert application %s to swarm stack error: %s", a.Name, err.Error())
+		}
+
+		res, err := Marshal(cfg)
+		if err != nil {
+			return fmt.Errorf("marshal swarm stack %s error: %s", a.Name, err.Error())
+		}
+
+		filename := path + "/" + a.GetID() + ".yml"
+		err = ioutil.WriteFile(filename, res, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write stack file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Convert application to swarm stack, services join an overlay network of the app,
+// and secret parameters are mounted from external docker secrets
+func Convert(a *project.Application, opt Option) (*types.Config, error) {
+	if len(a.Services) == 0 {
+		return nil, fmt.Errorf("no service found")
+	}
+
+	id := a.GetID()
+	network := id
+	cfg := &types.Config{
+		Version:  "3.8",
+		Services: types.Services{},
+		Networks: map[string]types.NetworkConfig{
+			network: {Driver: "overlay", Attachable: true},
+		},
+		Volumes: map[string]types.VolumeConfig{},
+		Secrets: map[string]types.SecretConfig{},
+	}
+
+	for _, s := range a.Services {
+		service := *s
+		service.ContainerName = ""
+		service.Restart = ""
+		service.DependsOn = nil
+		service.Deploy.RestartPolicy = restartPolicy(s.Restart)
+
+		if err := placement(&service.Deploy, a.Deploy); err != nil {
+			return nil, err
+		}
+
+		service.Networks = map[string]*types.ServiceNetworkConfig{}
+		if s.NetworkMode == "host" {
+			service.NetworkMode = ""
+			service.Networks["host"] = nil
+			cfg.Networks["host"] = types.NetworkConfig{External: types.External{Name: "host"}}
+		} else {
+			service.Networks[network] = nil
+		}
+
+		service.Ports = []types.ServicePortConfig{}
+		for _, port := range s.Ports {
+			port.Mode = opt.PortMode
+			if port.Protocol == "" {
+				port.Protocol = "tcp"
+			}
+			service.Ports = append(service.Ports, port)
+		}
+
+		service.Volumes = []types.ServiceVolumeConfig{}
+		for _, v := range s.Volumes {
+			switch {
+			case v.Type == "tmpfs":
+			case strings.HasPrefix(v.Source, project.DataPathPrefix):
+				v.Source = project.ResolveDataPath(v.Source, opt.DataPath)
+				v.Type = "bind"
+			case v.Source != "" && !strings.HasPrefix(v.Source, "/"):
+				// named volume
+				v.Type = "volume"
+				cfg.Volumes[v.Source] = types.VolumeConfig{}
+			}
+			service.Volumes = append(service.Volumes, v)
+		}
+
+		service.Environment = types.MappingWithEquals{}
+		service.Secrets = []types.ServiceSecretConfig{}
+		for name, value := range s.Environment {
+			p := a.GetParameter(project.ParameterTypeSecret, name)
+			if p == nil {
+				p = a.GetParameter(project.ParameterTypeVariable, name)
+			}
+			if p == nil || !p.IsSecret() {
+				service.Environment[name] = value
+				continue
+			}
+
+			// the value never stays in the environment
+			secret := id + "_" + strings.ToLower(name)
+			if opt.SecretFile {
+				file := "/run/secrets/" + secret
+				service.Environment[name+"_FILE"] = &file
+			}
+			service.Secrets = append(service.Secrets, types.ServiceSecretConfig{Source: secret})
+			cfg.Secrets[secret] = types.SecretConfig{External: types.External{External: true}}
+		}
+
+		cfg.Services = append(cfg.Services, service)
+	}
+
+	return cfg, nil
+}
+
+// restartPolicy of compose restart
+func restartPolicy(restart string) *types.RestartPolicy {
+	switch restart {
+	case "no":
+		return &types.RestartPolicy{Condition: "none"}
+	case "on-failure":
+		return &types.RestartPolicy{Condition: "on-failure"}
+	}
+
+	return &types.RestartPolicy{Condition: "any"}
+}
+
+// placement of app deploy metadata, the service deploy config takes priority
+func placement(deploy *types.DeployConfig, d *project.Deploy) error {
+	if d == nil {
+		return nil
+	}
+
+	if len(deploy.Placement.Constraints) == 0 {
+		deploy.Placement.Constraints = d.Constraints
+	}
+
+	var err error
+	if deploy.Resources.Limits == nil {
+		deploy.Resources.Limits, err = resource(d.Limits)
+		if err != nil {
+			return err
+		}
+	}
+	if deploy.Resources.Reservations == nil {
+		deploy.Resources.Reservations, err = resource(d.Reservations)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resource(r project.Resources) (*types.Resource, error) {
+	if r.CPUs == "" && r.Memory == "" {
+		return nil, nil
+	}
+
+	res := &types.Resource{NanoCPUs: r.CPUs}
+	if r.Memory != "" {
+		memory, err := units.RAMInBytes(r.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("parse memory %s error: %s", r.Memory, err.Error())
+		}
+		res.MemoryBytes = types.UnitBytes(memory)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package swarm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"github.com/yankghjh/selfhosted_store/cli/project/projecttest"
+)
+
+func TestConvertSecret(t *testing.T) {
+	a := projecttest.NewApp(projecttest.NewService("db", "postgres:13", map[string]string{"POSTGRES_PASSWORD": "changeme"}))
+	a.Parameters = append(a.Parameters, &project.Parameter{Type: project.ParameterTypeSecret, Target: "POSTGRES_PASSWORD"})
+
+	cases := []struct {
+		secretFile bool
+		env        []string
+	}{
+		{false, []string{}},
+		{true, []string{"POSTGRES_PASSWORD_FILE"}},
+	}
+
+	for _, c := range cases {
+		opt := DefaultOption()
+		opt.SecretFile = c.secretFile
+
+		cfg, err := Convert(a, opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		service := cfg.Services[0]
+		if len(service.Environment) != len(c.env) {
+			t.Errorf("secret_file %v: environment = %v, want %v", c.secretFile, service.Environment, c.env)
+		}
+		for _, name := range c.env {
+			if service.Environment[name] == nil {
+				t.Errorf("secret_file %v: environment has no %s", c.secretFile, name)
+			}
+		}
+		if len(service.Secrets) != 1 || service.Secrets[0].Source != "test_postgres_password" {
+			t.Errorf("secret_file %v: secrets = %v, want test_postgres_password", c.secretFile, service.Secrets)
+		}
+	}
+}
+
+func TestMarshalSecretHeader(t *testing.T) {
+	a := projecttest.NewApp(projecttest.NewService("db", "postgres:13", map[string]string{"POSTGRES_PASSWORD": "changeme"}))
+	a.Parameters = append(a.Parameters, &project.Parameter{Type: project.ParameterTypeSecret, Target: "POSTGRES_PASSWORD"})
+
+	cfg, err := Convert(a, DefaultOption())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(res), "# create the secrets") || !strings.Contains(string(res), "docker secret create test_postgres_password -") {
+		t.Errorf("stack file does not document the secret:\n%s", res)
+	}
+	if strings.Contains(string(res), "changeme") {
+		t.Errorf("stack file contains the secret value:\n%s", res)
+	}
+}
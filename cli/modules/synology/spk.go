@@ -0,0 +1,246 @@
+package synology
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	compose "github.com/yankghjh/selfhosted_store/cli/modules/docker-compose"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// WizardStep of dsm install wizard
+type WizardStep struct {
+	StepTitle string       `json:"step_title"`
+	Items     []WizardItem `json:"items"`
+}
+
+// WizardItem is a group of wizard inputs
+type WizardItem struct {
+	Type     string          `json:"type"`
+	Desc     string          `json:"desc,omitempty"`
+	Subitems []WizardSubitem `json:"subitems"`
+}
+
+// WizardSubitem is a wizard input
+type WizardSubitem struct {
+	Key          string `json:"key"`
+	Desc         string `json:"desc"`
+	DefaultValue string `json:"defaultValue,omitempty"`
+	EmptyValue   bool   `json:"emptyValue,omitempty"`
+}
+
+// PackageFiles of dsm 7 spk skeleton, file name to content,
+// converted is the application of container manager project
+func PackageFiles(a, converted *project.Application) (map[string][]byte, error) {
+	version := "1.0.0"
+	if image := project.ParseImage(a.Services[0].Image); image.Tag != "" && image.Tag != project.DefaultTag {
+		version = image.Tag
+	}
+
+	info := [][2]string{
+		{"package", a.GetID()},
+		{"version", version + "-0001"},
+		{"os_min_ver", "7.2-64570"},
+		{"displayname", a.Name},
+		{"description", a.Description},
+		{"arch", "noarch"},
+		{"maintainer", "selfhosted_store"},
+		{"install_dep_packages", "ContainerManager"},
+		{"thirdparty", "yes"},
+	}
+	if a.WebUI != nil {
+		scheme := a.WebUI.Scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		info = append(info,
+			[2]string{"adminprotocol", scheme},
+			[2]string{"adminport", fmt.Sprint(a.GetWebUIPort())},
+			[2]string{"adminurl", a.WebUI.Path},
+		)
+	}
+
+	lines := []string{}
+	for _, kv := range info {
+		lines = append(lines, kv[0]+"="+quoteInfo(kv[1]))
+	}
+
+	privilege, err := json.MarshalIndent(map[string]interface{}{
+		"defaults": map[string]string{"run-as": "package"},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := json.MarshalIndent(map[string]interface{}{
+		"docker-project": map[string]interface{}{
+			"preload-image": "false",
+			"projects": []map[string]string{
+				{"name": a.GetID(), "path": "docker"},
+			},
+		},
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	steps := NewWizard(a)
+	composeFile, err := compose.Encoder(WizardApplication(converted, steps))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{
+		"INFO":                        []byte(strings.Join(lines, "\n") + "\n"),
+		"conf/privilege":              privilege,
+		"conf/resource":               resource,
+		"package/docker/compose.yaml": composeFile,
+	}
+
+	if len(steps) > 0 {
+		wizard, err := json.MarshalIndent(steps, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files["WIZARD_UIFILES/install_uifile"] = wizard
+		files["scripts/postinst"] = []byte(PostInst(steps))
+	}
+
+	return files, nil
+}
+
+// NewWizard from the environment and parameters of application
+func NewWizard(a *project.Application) []WizardStep {
+	steps := []WizardStep{}
+
+	for _, service := range a.Services {
+		names := []string{}
+		for name := range service.Environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		inputs := WizardItem{Type: "textfield", Subitems: []WizardSubitem{}}
+		passwords := WizardItem{Type: "password", Subitems: []WizardSubitem{}}
+		for _, name := range names {
+			subitem := WizardSubitem{
+				Key:        wizardKey(a, service.Name, name),
+				Desc:       name,
+				EmptyValue: true,
+			}
+			if value := service.Environment[name]; value != nil {
+				subitem.DefaultValue = *value
+			}
+
+			p := a.GetParameter(project.ParameterTypeSecret, name)
+			if p == nil {
+				p = a.GetParameter(project.ParameterTypeVariable, name)
+			}
+			if p != nil {
+				if p.IsAdvanced() {
+					continue
+				}
+				subitem.Desc = p.GetLabel()
+				subitem.EmptyValue = !p.Required
+			}
+
+			if p != nil && p.IsSecret() {
+				passwords.Subitems = append(passwords.Subitems, subitem)
+			} else {
+				inputs.Subitems = append(inputs.Subitems, subitem)
+			}
+		}
+
+		step := WizardStep{StepTitle: a.Name, Items: []WizardItem{}}
+		if len(a.Services) > 1 {
+			step.StepTitle += " - " + service.Name
+		}
+		for _, item := range []WizardItem{inputs, passwords} {
+			if len(item.Subitems) > 0 {
+				step.Items = append(step.Items, item)
+			}
+		}
+		if len(step.Items) > 0 {
+			steps = append(steps, step)
+		}
+	}
+
+	return steps
+}
+
+// WizardApplication of a copy of application, the environment in wizard is
+// interpolated from the .env file written by postinst
+func WizardApplication(a *project.Application, steps []WizardStep) *project.Application {
+	keys := map[string]bool{}
+	for _, step := range steps {
+		for _, item := range step.Items {
+			for _, subitem := range item.Subitems {
+				keys[subitem.Key] = true
+			}
+		}
+	}
+
+	converted := *a
+	converted.Services = []*types.ServiceConfig{}
+	for _, s := range a.Services {
+		service := *s
+		service.Environment = types.MappingWithEquals{}
+		for name, v := range s.Environment {
+			key := wizardKey(a, s.Name, name)
+			if keys[key] {
+				value := "${" + strings.ToUpper(key)
+				if v != nil {
+					value += "-" + strings.Replace(*v, "$", "$$", -1)
+				}
+				value += "}"
+				v = &value
+			}
+			service.Environment[name] = v
+		}
+		converted.Services = append(converted.Services, &service)
+	}
+
+	return &converted
+}
+
+// PostInst script writes the wizard values, which dsm passes as environment
+// of scripts, to the .env file of compose project on installation
+func PostInst(steps []WizardStep) string {
+	b := &strings.Builder{}
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("ENV_FILE=\"${SYNOPKG_PKGDEST}/docker/.env\"\n\n")
+	b.WriteString("quote() {\n")
+	b.WriteString("\tprintf '\"%s\"' \"$(printf '%s' \"$1\" | sed -e 's/[\\\\\"$]/\\\\&/g')\"\n")
+	b.WriteString("}\n\n")
+	b.WriteString("if [ \"${SYNOPKG_PKG_STATUS}\" = \"INSTALL\" ]; then\n")
+	b.WriteString("\t{\n")
+	for _, step := range steps {
+		for _, item := range step.Items {
+			for _, subitem := range item.Subitems {
+				b.WriteString("\t\techo \"" + strings.ToUpper(subitem.Key) + "=$(quote \"${" + subitem.Key + "}\")\"\n")
+			}
+		}
+	}
+	b.WriteString("\t} > \"$ENV_FILE\"\n")
+	b.WriteString("fi\n\n")
+	b.WriteString("exit 0\n")
+
+	return b.String()
+}
+
+// wizardKey of environment, prefixed by the service name in multi services apps
+func wizardKey(a *project.Application, service, name string) string {
+	key := "wizard_"
+	if len(a.Services) > 1 {
+		key += strings.Replace(project.Slugify(service), "-", "_", -1) + "_"
+	}
+
+	return key + strings.ToLower(name)
+}
+
+func quoteInfo(s string) string {
+	return `"` + strings.Replace(strings.Replace(s, `"`, `\"`, -1), "\n", " ", -1) + `"`
+}
@@ -0,0 +1,42 @@
+package synology
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project/projecttest"
+)
+
+func TestPackageFilesWizard(t *testing.T) {
+	a := projecttest.NewApp(projecttest.NewService("test", "foo/test:1.0", map[string]string{"TZ": "UTC"}))
+
+	files, err := PackageFiles(a, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(files["WIZARD_UIFILES/install_uifile"]), `"key": "wizard_tz"`) {
+		t.Errorf("wizard has no wizard_tz:\n%s", files["WIZARD_UIFILES/install_uifile"])
+	}
+	if !strings.Contains(string(files["scripts/postinst"]), `WIZARD_TZ=$(quote "${wizard_tz}")`) {
+		t.Errorf("postinst does not write wizard_tz:\n%s", files["scripts/postinst"])
+	}
+	if !strings.Contains(string(files["package/docker/compose.yaml"]), "TZ: ${WIZARD_TZ-UTC}") {
+		t.Errorf("compose does not read WIZARD_TZ:\n%s", files["package/docker/compose.yaml"])
+	}
+}
+
+func TestPackageFilesWithoutWizard(t *testing.T) {
+	a := projecttest.NewApp(projecttest.NewService("test", "foo/test:1.0", nil))
+
+	files, err := PackageFiles(a, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"WIZARD_UIFILES/install_uifile", "scripts/postinst"} {
+		if _, ok := files[name]; ok {
+			t.Errorf("%s is written for app without environment", name)
+		}
+	}
+}
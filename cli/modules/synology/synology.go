@@ -0,0 +1,129 @@
+package synology
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	compose "github.com/yankghjh/selfhosted_store/cli/modules/docker-compose"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("synology", Generater)
+}
+
+// Option for synology projects
+type Option struct {
+	DataPath string
+	PUID     string
+	PGID     string
+	SPK      bool
+}
+
+// Generater synology container manager projects, and spk skeletons optionally
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("data_path", "/volume1/docker")
+	o.Config.SetDefault("puid", "1026")
+	o.Config.SetDefault("pgid", "100")
+	opt := Option{
+		DataPath: strings.TrimSuffix(o.Config.GetString("data_path"), "/"),
+		PUID:     o.Config.GetString("puid"),
+		PGID:     o.Config.GetString("pgid"),
+		SPK:      o.Config.GetBool("spk"),
+	}
+
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		converted := ConvertApplication(a, opt)
+		composeFile, err := compose.Encoder(converted)
+		if err != nil {
+			return fmt.Errorf("encode compose file of %s error: %s", a.Name, err.Error())
+		}
+
+		files := map[string][]byte{
+			a.GetID() + "/compose.yaml": composeFile,
+		}
+
+		if opt.SPK {
+			spk, err := PackageFiles(a, converted)
+			if err != nil {
+				return fmt.Errorf("create spk of %s error: %s", a.Name, err.Error())
+			}
+			for name, content := range spk {
+				files["spk/"+a.GetID()+"/"+name] = content
+			}
+		}
+
+		for name, content := range files {
+			filename := o.Project.GetDistPath("synology", name)
+			os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+			err := ioutil.WriteFile(filename, content, os.ModePerm)
+			if err != nil {
+				return fmt.Errorf("write file [%s] error: %s", filename, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// ConvertApplication rewrite volumes to the shared folder of synology,
+// and set PUID, PGID defaults
+func ConvertApplication(a *project.Application, opt Option) *project.Application {
+	converted := *a
+	converted.Services = []*types.ServiceConfig{}
+
+	root := opt.DataPath + "/" + a.GetID()
+	for _, s := range a.Services {
+		service := *s
+
+		service.Volumes = []types.ServiceVolumeConfig{}
+		for _, v := range s.Volumes {
+			switch {
+			case v.Type == "tmpfs":
+			case v.Source == project.DataPathPrefix || strings.HasPrefix(v.Source, project.DataPathPrefix+"/"):
+				v.Source = project.ResolveDataPath(v.Source, opt.DataPath)
+			case v.Source == "":
+				v.Source = root + "/" + project.Slugify(v.Target)
+			case strings.HasPrefix(v.Source, opt.DataPath+"/"):
+			case strings.HasPrefix(v.Source, "/"):
+				v.Source = root + v.Source
+			default:
+				// named volume
+				v.Source = root + "/" + v.Source
+			}
+			if v.Type == "volume" {
+				v.Type = "bind"
+				v.Volume = nil
+			}
+			service.Volumes = append(service.Volumes, v)
+		}
+
+		service.Environment = types.MappingWithEquals{}
+		for k, v := range s.Environment {
+			service.Environment[k] = v
+		}
+		for k, v := range map[string]string{"PUID": opt.PUID, "PGID": opt.PGID} {
+			value := v
+			if current := service.Environment[k]; current == nil || *current == "" {
+				service.Environment[k] = &value
+			}
+		}
+
+		converted.Services = append(converted.Services, &service)
+	}
+
+	return &converted
+}
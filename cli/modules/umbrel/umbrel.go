@@ -0,0 +1,234 @@
+package umbrel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func init() {
+	project.RegisterGenerater("umbrel", Generater)
+}
+
+const (
+	dataDir         = "${APP_DATA_DIR}"
+	proxyService    = "app_proxy"
+	defaultCategory = "utilities"
+)
+
+// Store is the umbrel-app-store.yml of community app store
+type Store struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+}
+
+// Manifest is the umbrel-app.yml of app, unknown fields are omitted
+type Manifest struct {
+	ManifestVersion int      `yaml:"manifestVersion"`
+	ID              string   `yaml:"id"`
+	Category        string   `yaml:"category"`
+	Name            string   `yaml:"name"`
+	Version         string   `yaml:"version"`
+	Tagline         string   `yaml:"tagline"`
+	Description     string   `yaml:"description"`
+	Developer       string   `yaml:"developer"`
+	Website         string   `yaml:"website,omitempty"`
+	Dependencies    []string `yaml:"dependencies"`
+	Repo            string   `yaml:"repo,omitempty"`
+	Support         string   `yaml:"support,omitempty"`
+	Port            uint32   `yaml:"port,omitempty"`
+	Gallery         []string `yaml:"gallery"`
+	Path            string   `yaml:"path,omitempty"`
+	DefaultUsername string   `yaml:"defaultUsername,omitempty"`
+	DefaultPassword string   `yaml:"defaultPassword,omitempty"`
+	Submitter       string   `yaml:"submitter"`
+	Submission      string   `yaml:"submission,omitempty"`
+	Icon            string   `yaml:"icon,omitempty"`
+}
+
+// Generater umbrel community app store
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	o.Config.SetDefault("id", "selfhosted")
+	o.Config.SetDefault("name", "Selfhosted Store")
+	o.Config.SetDefault("submitter", "selfhosted_store")
+	store := Store{
+		ID:   o.Config.GetString("id"),
+		Name: o.Config.GetString("name"),
+	}
+
+	res, err := yaml.Marshal(store)
+	if err != nil {
+		return fmt.Errorf("marshal umbrel app store error: %s", err.Error())
+	}
+	files := map[string][]byte{"umbrel-app-store.yml": res}
+
+	for _, a := range apps {
+		if len(a.Services) == 0 {
+			continue
+		}
+
+		id := store.ID + "-" + a.GetID()
+		manifest, err := yaml.Marshal(NewManifest(a, id, o.Config.GetString("submitter")))
+		if err != nil {
+			return fmt.Errorf("marshal umbrel app %s error: %s", a.Name, err.Error())
+		}
+		files[id+"/umbrel-app.yml"] = manifest
+
+		compose, err := yaml.Marshal(Convert(a, id))
+		if err != nil {
+			return fmt.Errorf("convert application %s to umbrel app error: %s", a.Name, err.Error())
+		}
+		files[id+"/docker-compose.yml"] = compose
+	}
+
+	for name, content := range files {
+		filename := o.Project.GetDistPath("umbrel", name)
+		os.MkdirAll(filepath.Dir(filename), os.ModePerm)
+		err := ioutil.WriteFile(filename, content, os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// NewManifest of application, id is prefixed with the store id
+func NewManifest(a *project.Application, id, submitter string) *Manifest {
+	m := &Manifest{
+		ManifestVersion: 1,
+		ID:              id,
+		Category:        defaultCategory,
+		Name:            a.Name,
+		Version:         "latest",
+		Tagline:         a.Description,
+		Description:     a.Overview,
+		Developer:       submitter,
+		Dependencies:    []string{},
+		Gallery:         []string{},
+		Submitter:       submitter,
+		Icon:            a.Icon,
+	}
+
+	if len(a.Category) > 0 && a.Category[0] != "" {
+		m.Category = strings.ToLower(a.Category[0])
+	}
+	if m.Description == "" {
+		m.Description = a.Description
+	}
+	if m.Tagline == "" {
+		m.Tagline = a.Name
+	}
+	if m.Description == "" {
+		m.Description = m.Tagline
+	}
+	if image := project.ParseImage(a.Services[0].Image); image.Tag != "" {
+		m.Version = image.Tag
+	}
+	if l := a.GetLink(project.LinkTypeProject); l != nil {
+		m.Website = l.URL
+		m.Repo = l.URL
+		m.Submission = l.URL
+	}
+	if l := a.GetLink(project.LinkTypeSupport); l != nil {
+		m.Support = l.URL
+	}
+	if a.WebUI != nil {
+		m.Port = a.GetWebUIPort()
+		m.Path = a.WebUI.Path
+		if m.Path == "/" {
+			m.Path = ""
+		}
+	}
+
+	return m
+}
+
+// Convert application to umbrel compose file, volumes except system paths
+// are moved to ${APP_DATA_DIR}
+// and the web ui is served by app_proxy instead of a published port
+func Convert(a *project.Application, id string) *types.Config {
+	services := types.Services{}
+
+	for _, s := range a.Services {
+		service := *s
+		// app_proxy finds the container by the compose generated name
+		service.ContainerName = ""
+		if service.Restart == "" {
+			service.Restart = "on-failure"
+		}
+
+		service.Ports = []types.ServicePortConfig{}
+		for _, port := range s.Ports {
+			if a.WebUI != nil && port.Target == a.WebUI.Port {
+				host, target := id+"_"+s.Name+"_1", strconv.Itoa(int(port.Target))
+				if s.NetworkMode == "host" {
+					host = "host.docker.internal"
+				}
+				services = append(services, types.ServiceConfig{
+					Name: proxyService,
+					Environment: types.MappingWithEquals{
+						"APP_HOST": &host,
+						"APP_PORT": &target,
+					},
+				})
+				continue
+			}
+			service.Ports = append(service.Ports, port)
+		}
+
+		service.Volumes = []types.ServiceVolumeConfig{}
+		for _, v := range s.Volumes {
+			if v.Type != "tmpfs" {
+				switch {
+				case strings.HasPrefix(v.Source, project.DataPathPrefix):
+					v.Source = dataDir + strings.TrimPrefix(v.Source, project.DataPathPrefix)
+				case v.Source == "":
+					v.Source = dataDir + "/" + project.Slugify(v.Target)
+				case !strings.HasPrefix(v.Source, "/"):
+					// named volume
+					v.Source = dataDir + "/" + v.Source
+				case !isSystemPath(v.Source):
+					v.Source = dataDir + path.Clean(v.Source)
+				}
+				v.Type = "bind"
+				v.Volume = nil
+			}
+			service.Volumes = append(service.Volumes, v)
+		}
+
+		services = append(services, service)
+	}
+
+	return &types.Config{
+		Version:  "3.7",
+		Services: services,
+	}
+}
+
+// system paths of host are mounted as is, like the docker socket and devices
+var systemPaths = []string{"/var/run/docker.sock", "/run", "/dev", "/proc", "/sys", "/etc/localtime", "/etc/timezone"}
+
+func isSystemPath(source string) bool {
+	source = path.Clean(source)
+	for _, p := range systemPaths {
+		if source == p || strings.HasPrefix(source, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
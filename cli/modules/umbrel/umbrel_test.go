@@ -0,0 +1,86 @@
+package umbrel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConvertVolumes(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Samba"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:  "samba",
+		Image: "dperson/samba",
+		Volumes: []types.ServiceVolumeConfig{
+			{Type: "bind", Source: "!data/config", Target: "/config"},
+			{Type: "bind", Source: "/yacht", Target: "/yacht"},
+			{Type: "bind", Source: "/data/", Target: "/data"},
+			{Type: "volume", Source: "cache", Target: "/cache"},
+			{Type: "volume", Target: "/tmp/anonymous"},
+			{Type: "bind", Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"},
+			{Type: "bind", Source: "/dev/dri", Target: "/dev/dri"},
+		},
+	})
+
+	cfg := Convert(a, "selfhosted-samba")
+
+	want := []string{
+		"${APP_DATA_DIR}/config",
+		"${APP_DATA_DIR}/yacht",
+		"${APP_DATA_DIR}/data",
+		"${APP_DATA_DIR}/cache",
+		"${APP_DATA_DIR}/tmp-anonymous",
+		"/var/run/docker.sock",
+		"/dev/dri",
+	}
+	volumes := cfg.Services[0].Volumes
+	for i, source := range want {
+		if volumes[i].Source != source {
+			t.Errorf("volume %s: source = %s, want %s", volumes[i].Target, volumes[i].Source, source)
+		}
+	}
+}
+
+func TestConvertProxy(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Yarr"
+	a.WebUI = &project.WebUI{Scheme: "http", Port: 7070, Path: "/"}
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:  "yarr",
+		Image: "yarr",
+		Ports: []types.ServicePortConfig{{Target: 7070, Published: 7070}, {Target: 9090, Published: 9090}},
+	})
+
+	cfg := Convert(a, "selfhosted-yarr")
+	if len(cfg.Services) != 2 || cfg.Services[0].Name != proxyService {
+		t.Fatalf("services = %+v, want app_proxy first", cfg.Services)
+	}
+	if *cfg.Services[0].Environment["APP_HOST"] != "selfhosted-yarr_yarr_1" || *cfg.Services[0].Environment["APP_PORT"] != "7070" {
+		t.Errorf("app_proxy environment = %v", cfg.Services[0].Environment)
+	}
+	if ports := cfg.Services[1].Ports; len(ports) != 1 || ports[0].Target != 9090 {
+		t.Errorf("ports = %+v, want 9090 only", ports)
+	}
+}
+
+func TestNewManifestOmitsEmptyFields(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Yarr"
+	a.Services = append(a.Services, &types.ServiceConfig{Name: "yarr", Image: "yarr:1.0"})
+
+	m := NewManifest(a, "selfhosted-yarr", "selfhosted_store")
+	res, err := yaml.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(res), `""`) {
+		t.Errorf("manifest has empty fields:\n%s", res)
+	}
+	if m.Tagline != "Yarr" || m.Description != "Yarr" || m.Version != "1.0" {
+		t.Errorf("manifest = %+v", m)
+	}
+}
@@ -0,0 +1,152 @@
+package unraid
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// Duplicate resolve modes
+const (
+	DuplicateModeKeep     = "keep"
+	DuplicateModePrefer   = "prefer"
+	DuplicateModeVariants = "variants"
+)
+
+var defaultDuplicateRules = []string{"official", "maintainer", "downloads", "updated"}
+
+// Deduplicator pick the preferred template from apps of the same image
+type Deduplicator struct {
+	Mode        string
+	Maintainers []string
+	Rules       []string
+}
+
+// NewDeduplicator from the duplicates config of loader
+func NewDeduplicator(cfg *viper.Viper) (*Deduplicator, error) {
+	if cfg == nil {
+		cfg = viper.New()
+	}
+	cfg.SetDefault("mode", DuplicateModeKeep)
+	cfg.SetDefault("rules", defaultDuplicateRules)
+
+	d := &Deduplicator{
+		Mode:        cfg.GetString("mode"),
+		Maintainers: cfg.GetStringSlice("maintainers"),
+		Rules:       cfg.GetStringSlice("rules"),
+	}
+
+	switch d.Mode {
+	case DuplicateModeKeep, DuplicateModePrefer, DuplicateModeVariants:
+	default:
+		return nil, fmt.Errorf("unknown duplicates mode [%s]", d.Mode)
+	}
+
+	for _, rule := range d.Rules {
+		switch rule {
+		case "official", "maintainer", "downloads", "updated":
+		default:
+			return nil, fmt.Errorf("unknown duplicates rule [%s]", rule)
+		}
+	}
+
+	return d, nil
+}
+
+// Resolve duplicate apps, the preferred app keeps the position of its group
+func (d *Deduplicator) Resolve(apps []*Application) []*Application {
+	if d.Mode == DuplicateModeKeep {
+		return apps
+	}
+
+	groups := map[string][]*Application{}
+	names := []string{}
+	for _, a := range apps {
+		name := project.ParseImage(a.Repository).Name()
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], a)
+	}
+
+	result := []*Application{}
+	for _, name := range names {
+		group := groups[name]
+		preferred := group[0]
+		for _, a := range group[1:] {
+			if d.less(preferred, a) {
+				preferred = a
+			}
+		}
+
+		if d.Mode == DuplicateModeVariants {
+			for _, a := range group {
+				if a != preferred {
+					preferred.variants = append(preferred.variants, a)
+				}
+			}
+		}
+
+		result = append(result, preferred)
+	}
+
+	return result
+}
+
+// less return true when b is preferred over a
+func (d *Deduplicator) less(a, b *Application) bool {
+	for _, rule := range d.Rules {
+		var x, y int64
+		switch rule {
+		case "official":
+			x, y = boolToInt(a.isOfficial()), boolToInt(b.isOfficial())
+		case "maintainer":
+			// earlier maintainer in config is preferred
+			x, y = -d.maintainerIndex(a), -d.maintainerIndex(b)
+		case "downloads":
+			x, y = cast.ToInt64(a.Downloads), cast.ToInt64(b.Downloads)
+		case "updated":
+			x, y = cast.ToInt64(a.LastUpdate), cast.ToInt64(b.LastUpdate)
+		}
+
+		if x != y {
+			return x < y
+		}
+	}
+
+	return false
+}
+
+func (d *Deduplicator) maintainerIndex(a *Application) int64 {
+	maintainer := a.GetMaintainer()
+	for i, pattern := range d.Maintainers {
+		if matchGlobs([]string{pattern}, maintainer) {
+			return int64(i)
+		}
+	}
+
+	return int64(len(d.Maintainers))
+}
+
+// isOfficial when the template is maintained by the image owner
+func (a *Application) isOfficial() bool {
+	image := project.ParseImage(a.Repository)
+	namespace := strings.SplitN(image.Repository, "/", 2)[0]
+	if image.Registry == project.DefaultRegistry && namespace == project.DefaultNamespace {
+		return true
+	}
+
+	return namespace == strings.ToLower(a.GetMaintainer())
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,164 @@
+package unraid
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// Filter rule for unraid applications
+type Filter struct {
+	Name string
+	// Remove return true when the application should be removed
+	Remove func(a *Application) bool
+}
+
+// NewFilters from the filter config of loader
+func NewFilters(cfg *viper.Viper) []*Filter {
+	if cfg == nil {
+		cfg = viper.New()
+	}
+	cfg.SetDefault("deprecated", false)
+	cfg.SetDefault("blacklisted", false)
+	cfg.SetDefault("moderated", true)
+	cfg.SetDefault("beta", true)
+
+	filters := []*Filter{}
+
+	if !cfg.GetBool("deprecated") {
+		filters = append(filters, &Filter{
+			Name:   "deprecated",
+			Remove: func(a *Application) bool { return cast.ToBool(a.Deprecated) },
+		})
+	}
+
+	if !cfg.GetBool("blacklisted") {
+		filters = append(filters, &Filter{
+			Name:   "blacklisted",
+			Remove: func(a *Application) bool { return cast.ToBool(a.Blacklist) },
+		})
+	}
+
+	if !cfg.GetBool("moderated") {
+		filters = append(filters, &Filter{
+			Name:   "moderated",
+			Remove: func(a *Application) bool { return a.ModeratorComment != "" },
+		})
+	}
+
+	if !cfg.GetBool("beta") {
+		filters = append(filters, &Filter{
+			Name:   "beta",
+			Remove: func(a *Application) bool { return cast.ToBool(a.Beta) },
+		})
+	}
+
+	if version := cfg.GetString("unraid_version"); version != "" {
+		filters = append(filters, &Filter{
+			Name: "unraid_version",
+			Remove: func(a *Application) bool {
+				if min := string(a.MinVer); min != "" && compareVersion(version, min) < 0 {
+					return true
+				}
+				max := string(a.MaxVer)
+				return max != "" && compareVersion(version, max) > 0
+			},
+		})
+	}
+
+	globFilters := []struct {
+		name  string
+		value func(a *Application) string
+	}{
+		{"maintainers", (*Application).GetMaintainer},
+		{"repositories", func(a *Application) string { return a.Repository }},
+	}
+
+	for _, g := range globFilters {
+		value := g.value
+		if include := cfg.GetStringSlice(g.name + ".include"); len(include) > 0 {
+			filters = append(filters, &Filter{
+				Name:   g.name + ".include",
+				Remove: func(a *Application) bool { return !matchGlobs(include, value(a)) },
+			})
+		}
+
+		if exclude := cfg.GetStringSlice(g.name + ".exclude"); len(exclude) > 0 {
+			filters = append(filters, &Filter{
+				Name:   g.name + ".exclude",
+				Remove: func(a *Application) bool { return matchGlobs(exclude, value(a)) },
+			})
+		}
+	}
+
+	return filters
+}
+
+// MatchFilters return the first filter which removes the application
+func MatchFilters(filters []*Filter, a *Application) *Filter {
+	for _, f := range filters {
+		if f.Remove(a) {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// GetMaintainer of application, fallback to the template repository name
+func (a *Application) GetMaintainer() string {
+	if author := string(a.Author); author != "" {
+		return author
+	}
+
+	return strings.TrimSuffix(string(a.Repo), "'s Repository")
+}
+
+// matchGlobs of case insensitive patterns, * matches any characters including /
+// and ? matches a single character
+func matchGlobs(patterns []string, value string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		if globPattern(pattern).MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func globPattern(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(strings.ToLower(pattern))
+	expr = strings.Replace(expr, `\*`, ".*", -1)
+	expr = strings.Replace(expr, `\?`, ".", -1)
+
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// compareVersion of unraid like 6.9.0-rc2, suffix is ignored
+func compareVersion(a, b string) int {
+	as := strings.Split(strings.SplitN(a, "-", 2)[0], ".")
+	bs := strings.Split(strings.SplitN(b, "-", 2)[0], ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
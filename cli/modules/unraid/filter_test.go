@@ -0,0 +1,110 @@
+package unraid
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestMatchGlobs(t *testing.T) {
+	cases := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"ghcr.io/*", "ghcr.io/org/image", true},
+		{"ghcr.io/*", "docker.io/org/image", false},
+		{"linuxserver/*", "LinuxServer/plex", true},
+		{"*/plex", "lscr.io/linuxserver/plex", true},
+		{"plex?nc", "plexinc", true},
+		{"plex.inc", "plexxinc", false},
+	}
+
+	for _, c := range cases {
+		if got := matchGlobs([]string{c.pattern}, c.value); got != c.want {
+			t.Errorf("matchGlobs(%q, %q) = %v, want %v", c.pattern, c.value, got, c.want)
+		}
+	}
+}
+
+func TestLoadApplicationsNumericFields(t *testing.T) {
+	payload := []byte(`{"apps":1,"applist":[{"Name":"Test","Repository":"foo/test","Author":1234,"Repo":5678,"MinVer":6.9,"MaxVer":"6.12.0"}]}`)
+
+	apps, err := LoadApplications(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := apps[0].GetMaintainer(); got != "1234" {
+		t.Errorf("maintainer = %q, want 1234", got)
+	}
+
+	cfg := viper.New()
+	cfg.Set("unraid_version", "6.8.3")
+	if f := MatchFilters(NewFilters(cfg), apps[0]); f == nil || f.Name != "unraid_version" {
+		t.Errorf("app requires 6.9 is not removed on 6.8.3")
+	}
+
+	cfg.Set("unraid_version", "6.9.2")
+	if f := MatchFilters(NewFilters(cfg), apps[0]); f != nil {
+		t.Errorf("app is removed by %s on 6.9.2", f.Name)
+	}
+}
+
+func TestLoadApplicationsVersionNumbers(t *testing.T) {
+	payload := []byte(`{"apps":1,"applist":[{"Name":"Test","Repository":"foo/test","MinVer":6.10}]}`)
+
+	apps, err := LoadApplications(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apps[0].MinVer != "6.10" {
+		t.Errorf("MinVer = %q, want 6.10", apps[0].MinVer)
+	}
+
+	cfg := viper.New()
+	cfg.Set("unraid_version", "6.9.2")
+	if f := MatchFilters(NewFilters(cfg), apps[0]); f == nil || f.Name != "unraid_version" {
+		t.Errorf("app requires 6.10 is not removed on 6.9.2")
+	}
+}
+
+func TestText(t *testing.T) {
+	cases := []struct {
+		json string
+		want Text
+	}{
+		{`"plain"`, "plain"},
+		{`6.10`, "6.10"},
+		{`12`, "12"},
+		{`true`, "true"},
+		{`null`, ""},
+		{`["first","second"]`, "first\nsecond"},
+		{`{"a":1}`, `{"a":1}`},
+	}
+
+	for _, c := range cases {
+		var got Text
+		if err := json.Unmarshal([]byte(c.json), &got); err != nil {
+			t.Errorf("unmarshal %s error: %s", c.json, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("unmarshal %s = %q, want %q", c.json, got, c.want)
+		}
+	}
+}
+
+func TestLoadApplicationsTolerantFields(t *testing.T) {
+	payload := []byte(`{"apps":1,"applist":[{"Name":"Test","Repository":"foo/test",` +
+		`"Changes":["fixed a","fixed b"],"WebUI":"http://[IP]:8080/","Support":1,"Project":null,` +
+		`"DonateLink":false,"Registry":"https://hub.docker.com/r/foo/test","ModeratorComment":0}]}`)
+
+	apps, err := LoadApplications(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apps[0].Changes != "fixed a\nfixed b" || apps[0].Support != "1" || apps[0].Project != "" {
+		t.Errorf("app = %+v", apps[0])
+	}
+}
@@ -0,0 +1,262 @@
+package unraid
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func init() {
+	project.RegisterGenerater("unraid", Generater)
+}
+
+var defaultDataPath = "/mnt/user/appdata"
+
+// Template struct for unraid container template version 2
+type Template struct {
+	XMLName     xml.Name         `xml:"Container"`
+	Version     string           `xml:"version,attr"`
+	Name        string           `xml:"Name"`
+	Repository  string           `xml:"Repository"`
+	Registry    string           `xml:"Registry"`
+	Network     string           `xml:"Network"`
+	Privileged  bool             `xml:"Privileged"`
+	Support     string           `xml:"Support"`
+	Project     string           `xml:"Project"`
+	Overview    string           `xml:"Overview"`
+	Category    string           `xml:"Category"`
+	WebUI       string           `xml:"WebUI"`
+	Icon        string           `xml:"Icon"`
+	ExtraParams string           `xml:"ExtraParams"`
+	PostArgs    string           `xml:"PostArgs"`
+	DonateLink  string           `xml:"DonateLink"`
+	Description string           `xml:"Description"`
+	Configs     []TemplateConfig `xml:"Config"`
+}
+
+// TemplateConfig for unraid template port, path, variable, device and label
+type TemplateConfig struct {
+	Name        string `xml:"Name,attr"`
+	Target      string `xml:"Target,attr"`
+	Default     string `xml:"Default,attr"`
+	Mode        string `xml:"Mode,attr"`
+	Description string `xml:"Description,attr"`
+	Type        string `xml:"Type,attr"`
+	Display     string `xml:"Display,attr"`
+	Required    bool   `xml:"Required,attr"`
+	Mask        bool   `xml:"Mask,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Generater unraid templates, one xml file per app
+func Generater(o *project.Operator) error {
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	dataPath := o.Config.GetString("data_path")
+	if dataPath == "" {
+		dataPath = defaultDataPath
+	}
+
+	path := o.Project.GetDistPath("templates", "unraid")
+	os.MkdirAll(path, os.ModePerm)
+
+	for _, a := range apps {
+		t, err := ConvertApplication(a, dataPath)
+		if err != nil {
+			return fmt.Errorf("convert application %s to unraid template error: %s", a.Name, err.Error())
+		}
+
+		res, err := xml.MarshalIndent(t, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal template %s error: %s", a.Name, err.Error())
+		}
+
+		filename := path + "/" + a.GetID() + ".xml"
+		err = ioutil.WriteFile(filename, append([]byte(xml.Header), res...), os.ModePerm)
+		if err != nil {
+			return fmt.Errorf("write template file [%s] error: %s", filename, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ConvertApplication convert the first service of application to unraid template
+func ConvertApplication(a *project.Application, dataPath string) (*Template, error) {
+	if len(a.Services) == 0 {
+		return nil, fmt.Errorf("no service found")
+	}
+	service := a.Services[0]
+
+	t := &Template{
+		Version:     "2",
+		Name:        service.ContainerName,
+		Repository:  service.Image,
+		Network:     service.NetworkMode,
+		Privileged:  service.Privileged,
+		Overview:    a.Overview,
+		Icon:        a.Icon,
+		Description: a.Description,
+		PostArgs:    strings.Join(service.Command, " "),
+		Configs:     []TemplateConfig{},
+	}
+
+	if t.Name == "" {
+		t.Name = a.GetID()
+	}
+	if t.Overview == "" {
+		t.Overview = a.Description
+	}
+	if t.Network == "" {
+		t.Network = "bridge"
+	}
+
+	categories := []string{}
+	for _, c := range a.Category {
+		if c == "" {
+			continue
+		}
+		if !strings.Contains(c, ":") {
+			c += ":"
+		}
+		categories = append(categories, c)
+	}
+	t.Category = strings.Join(categories, " ")
+
+	if a.WebUI != nil {
+		scheme := a.WebUI.Scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		t.WebUI = scheme + "://[IP]:[PORT:" + strconv.Itoa(int(a.WebUI.Port)) + "]" + a.WebUI.Path
+	}
+
+	if l := a.GetLink(project.LinkTypeSupport); l != nil {
+		t.Support = l.URL
+	}
+	if l := a.GetLink(project.LinkTypeProject); l != nil {
+		t.Project = l.URL
+	}
+	if l := a.GetLink(project.LinkTypeRegistry); l != nil {
+		t.Registry = l.URL
+	}
+	if l := a.GetLink(project.LinkTypeDonate); l != nil {
+		t.DonateLink = l.URL
+	}
+
+	params := []string{}
+	if service.Restart != "" && service.Restart != "no" {
+		params = append(params, "--restart="+service.Restart)
+	}
+	for _, c := range service.CapAdd {
+		params = append(params, "--cap-add="+c)
+	}
+	t.ExtraParams = strings.Join(params, " ")
+
+	for _, port := range service.Ports {
+		target := strconv.Itoa(int(port.Target))
+		published := strconv.Itoa(int(port.Published))
+		if port.Published == 0 {
+			published = target
+		}
+		t.Configs = append(t.Configs, newTemplateConfig(a, project.ParameterTypePort, target, TemplateConfig{
+			Name:        "Port " + target,
+			Target:      target,
+			Mode:        port.Protocol,
+			Description: "Container Port: " + target,
+			Value:       published,
+		}))
+	}
+
+	for _, volume := range service.Volumes {
+		mode := "rw"
+		if volume.ReadOnly {
+			mode = "ro"
+		}
+		t.Configs = append(t.Configs, newTemplateConfig(a, project.ParameterTypePath, volume.Target, TemplateConfig{
+			Name:        "Path " + volume.Target,
+			Target:      volume.Target,
+			Mode:        mode,
+			Description: "Container Path: " + volume.Target,
+			Value:       project.ResolveDataPath(volume.Source, dataPath),
+		}))
+	}
+
+	names := []string{}
+	for name := range service.Environment {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := ""
+		if point := service.Environment[name]; point != nil {
+			value = *point
+		}
+		t.Configs = append(t.Configs, newTemplateConfig(a, project.ParameterTypeVariable, name, TemplateConfig{
+			Name:        name,
+			Target:      name,
+			Description: "Container Variable: " + name,
+			Value:       value,
+		}))
+	}
+
+	for _, device := range service.Devices {
+		host := strings.SplitN(device, ":", 2)[0]
+		c := newTemplateConfig(a, project.ParameterTypeDevice, host, TemplateConfig{
+			Name:        "Device " + host,
+			Description: "Device: " + host,
+			Value:       host,
+		})
+		c.Target = ""
+		t.Configs = append(t.Configs, c)
+	}
+
+	labels := []string{}
+	for name := range service.Labels {
+		labels = append(labels, name)
+	}
+	sort.Strings(labels)
+	for _, name := range labels {
+		t.Configs = append(t.Configs, newTemplateConfig(a, project.ParameterTypeLabel, name, TemplateConfig{
+			Name:        name,
+			Target:      name,
+			Description: "Container Label: " + name,
+			Value:       service.Labels[name],
+		}))
+	}
+
+	return t, nil
+}
+
+// newTemplateConfig fill the config with the parameter metadata of application
+func newTemplateConfig(a *project.Application, t, target string, c TemplateConfig) TemplateConfig {
+	c.Type = t
+	c.Display = project.DisplayAlways
+	c.Default = c.Value
+
+	if p := a.GetParameter(t, target); p != nil {
+		c.Name = p.GetLabel()
+		if p.Description != "" {
+			c.Description = p.Description
+		}
+		if p.Display == project.DisplayHidden {
+			// hidden parameters are advanced, unraid has no plain hidden display
+			c.Display = "advanced-hide"
+		} else if p.Display != "" {
+			c.Display = p.Display
+		}
+		c.Required = p.Required
+		c.Mask = p.Mask
+	}
+
+	return c
+}
@@ -0,0 +1,31 @@
+package unraid
+
+import (
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestNewTemplateConfigDisplay(t *testing.T) {
+	cases := []struct {
+		display string
+		want    string
+	}{
+		{"", "always"},
+		{project.DisplayAlways, "always"},
+		{project.DisplayAdvanced, "advanced"},
+		{project.DisplayHidden, "advanced-hide"},
+	}
+
+	for _, c := range cases {
+		a := project.NewApplication()
+		a.Parameters = append(a.Parameters, &project.Parameter{
+			Type: project.ParameterTypeVariable, Target: "TZ", Display: c.display,
+		})
+
+		cfg := newTemplateConfig(a, project.ParameterTypeVariable, "TZ", TemplateConfig{Name: "TZ", Target: "TZ"})
+		if cfg.Display != c.want {
+			t.Errorf("display %q: Display = %q, want %q", c.display, cfg.Display, c.want)
+		}
+	}
+}
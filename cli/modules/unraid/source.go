@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/cli/cli/compose/types"
 	"github.com/spf13/cast"
@@ -31,10 +32,60 @@ type Application struct {
 	Data        interface{}
 	Config      interface{}
 
+	Author           Text
+	Repo             Text
+	Deprecated       interface{}
+	Blacklist        interface{}
+	Beta             interface{}
+	ModeratorComment Text
+	MinVer           Text
+	MaxVer           Text
+	Downloads        interface{}
+	Stars            interface{}
+	LastUpdate       interface{}
+	FirstSeen        interface{}
+	Changes          Text
+	WebUI            Text
+	Support          Text
+	Project          Text
+	DonateLink       Text
+	Registry         Text
+	Privileged       interface{}
+
 	network     map[string]*types.ServicePortConfig
 	volumn      map[string]*types.ServiceVolumeConfig
 	environment map[string]*string
+	devices     []string
 	networkMode string
+	parameters  []*project.Parameter
+	variants    []*Application
+}
+
+// Text field of feed, which is not always a string, numbers are kept as written
+// like 6.10, lists of strings are joined by lines and other values are kept as raw json
+type Text string
+
+// UnmarshalJSON of any json value
+func (t *Text) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = Text(s)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*t = Text(strings.Join(list, "\n"))
+		return nil
+	}
+
+	if raw := strings.TrimSpace(string(data)); raw != "null" {
+		*t = Text(raw)
+	} else {
+		*t = ""
+	}
+
+	return nil
 }
 
 // FeedFile struct for unraid community application feed
@@ -59,13 +110,50 @@ func Loader(o *project.Operator) error {
 		return err
 	}
 
+	deduplicator, err := NewDeduplicator(o.Config.Sub("duplicates"))
+	if err != nil {
+		return err
+	}
+
+	filters := NewFilters(o.Config.Sub("filter"))
+	removed := map[string]int{}
+	apps := []*Application{}
+
 	for _, a := range appList {
 		if a.Plugin || a.Repository == "" {
 			continue
 		}
 
+		if f := MatchFilters(filters, a); f != nil {
+			removed[f.Name]++
+			continue
+		}
+
+		apps = append(apps, a)
+	}
+
+	for _, f := range filters {
+		fmt.Printf("unraid loader %s: filter [%s] removed %d apps\n", o.Name, f.Name, removed[f.Name])
+	}
+
+	resolved := deduplicator.Resolve(apps)
+	if len(resolved) < len(apps) {
+		fmt.Printf("unraid loader %s: resolved %d duplicate apps\n", o.Name, len(apps)-len(resolved))
+	}
+
+	ids := map[string]int{}
+	for _, a := range resolved {
 		a.Parse()
-		o.Project.Apps = append(o.Project.Apps, a.ToProjectApplication())
+		app := a.ToProjectApplication()
+
+		// apps with the same name are renamed as name-2, name-3...
+		id := app.GetID()
+		ids[id]++
+		if ids[id] > 1 {
+			app.ID = id + "-" + strconv.Itoa(ids[id])
+		}
+
+		o.Project.Apps = append(o.Project.Apps, app)
 	}
 
 	return nil
@@ -91,6 +179,8 @@ func (a *Application) Parse() error {
 	a.network = map[string]*types.ServicePortConfig{}
 	a.volumn = map[string]*types.ServiceVolumeConfig{}
 	a.environment = map[string]*string{}
+	a.parameters = []*project.Parameter{}
+	a.devices = []string{}
 	// config
 	if a.Config != nil {
 		cfgs := []map[string]interface{}{}
@@ -121,25 +211,55 @@ func (a *Application) Parse() error {
 func (a *Application) ToProjectApplication() *project.Application {
 	app := project.NewApplication()
 
+	app.ID = project.Slugify(a.Name)
 	app.Name = a.Name
 	app.Description = a.Description
 	app.Overview = a.Overview
 	app.Icon = a.Icon
 	app.Category = strings.Split(a.Category, " ")
+	app.Parameters = a.parameters
+
+	app.Downloads = cast.ToInt64(a.Downloads)
+	app.Stars = cast.ToInt64(a.Stars)
+	app.LastUpdate = toTime(a.LastUpdate)
+	app.FirstSeen = toTime(a.FirstSeen)
+	app.Changes = string(a.Changes)
+	app.Source = &project.Source{Location: string(a.Repo)}
+
+	app.WebUI = project.ParseWebUI(string(a.WebUI))
+	links := []*project.Link{
+		{Type: project.LinkTypeSupport, URL: string(a.Support)},
+		{Type: project.LinkTypeProject, URL: string(a.Project)},
+		{Type: project.LinkTypeDonate, URL: string(a.DonateLink)},
+		{Type: project.LinkTypeRegistry, URL: string(a.Registry)},
+	}
+	for _, l := range links {
+		if l.URL != "" {
+			app.Links = append(app.Links, l)
+		}
+	}
 
 	app.Services = append(app.Services, a.GetServiceConfig())
 
+	for _, v := range a.variants {
+		v.Parse()
+		app.Variants = append(app.Variants, v.ToProjectApplication())
+	}
+
 	return app
 }
 
 // GetServiceConfig from application
 func (a *Application) GetServiceConfig() *types.ServiceConfig {
 	service := &types.ServiceConfig{}
+	service.Name = project.Slugify(a.Name)
 	service.ContainerName = a.Name
 	service.Environment = a.environment
 	service.Image = a.Repository
 	service.Restart = defaultRestartPolicy
 	service.NetworkMode = a.networkMode
+	service.Privileged = cast.ToBool(a.Privileged)
+	service.Devices = a.devices
 
 	ports := []types.ServicePortConfig{}
 	for _, p := range a.network {
@@ -163,6 +283,13 @@ func (a *Application) parseConfigItem(v map[string]interface{}) {
 		value = attributes["Default"]
 	}
 
+	// target of device is usually empty, the value is the device path
+	if attributes["Type"] == project.ParameterTypeDevice && attributes["Target"] == "" {
+		attributes["Target"] = value
+	}
+
+	a.addParameter(attributes, value)
+
 	switch attributes["Type"] {
 	case "Port":
 		a.addNetwork(&types.ServicePortConfig{
@@ -177,9 +304,54 @@ func (a *Application) parseConfigItem(v map[string]interface{}) {
 		})
 	case "Variable":
 		a.addEnvironment(attributes["Target"], value)
+	case "Device":
+		if value != "" {
+			a.devices = append(a.devices, value)
+		}
 	}
 }
 
+// displays of unraid config to parameter display, the -hide variants
+// are hidden from the container summary and become hidden parameters
+var displays = map[string]string{
+	"always":        project.DisplayAlways,
+	"advanced":      project.DisplayAdvanced,
+	"always-hide":   project.DisplayHidden,
+	"advanced-hide": project.DisplayHidden,
+}
+
+// toTime from unix timestamp of feed
+func toTime(v interface{}) time.Time {
+	ts := cast.ToInt64(v)
+	if ts <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(ts, 0).UTC()
+}
+
+func (a *Application) addParameter(attributes map[string]string, value string) {
+	if attributes["Target"] == "" {
+		return
+	}
+
+	display := displays[attributes["Display"]]
+	if display == "" {
+		display = project.DisplayAlways
+	}
+
+	a.parameters = append(a.parameters, &project.Parameter{
+		Type:        attributes["Type"],
+		Target:      attributes["Target"],
+		Name:        attributes["Name"],
+		Description: attributes["Description"],
+		Default:     value,
+		Display:     display,
+		Required:    cast.ToBool(attributes["Required"]),
+		Mask:        cast.ToBool(attributes["Mask"]),
+	})
+}
+
 func (a *Application) addNetwork(n *types.ServicePortConfig) {
 	if n.Protocol != "tcp" && n.Protocol != "udp" {
 		n.Protocol = "tcp"
@@ -0,0 +1,34 @@
+package unraid
+
+import (
+	"testing"
+
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestParseDisplay(t *testing.T) {
+	cases := []struct {
+		display string
+		want    string
+	}{
+		{"always", project.DisplayAlways},
+		{"advanced", project.DisplayAdvanced},
+		{"always-hide", project.DisplayHidden},
+		{"advanced-hide", project.DisplayHidden},
+		{"", project.DisplayAlways},
+	}
+
+	for _, c := range cases {
+		a := &Application{Config: []interface{}{
+			map[string]interface{}{
+				"@attributes": map[string]interface{}{"Type": "Variable", "Target": "TZ", "Display": c.display},
+				"value":       "UTC",
+			},
+		}}
+		a.Parse()
+
+		if len(a.parameters) != 1 || a.parameters[0].Display != c.want {
+			t.Errorf("display %q: parameters = %+v, want %s", c.display, a.parameters, c.want)
+		}
+	}
+}
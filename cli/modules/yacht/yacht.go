@@ -48,9 +48,10 @@ type VolumeConfig struct {
 
 // EnvironmentConfig for Yacht template environment
 type EnvironmentConfig struct {
-	Name    string `json:"name"`
-	Label   string `json:"label"`
-	Default string `json:"default"`
+	Name        string `json:"name"`
+	Label       string `json:"label"`
+	Default     string `json:"default"`
+	Description string `json:"description,omitempty"`
 }
 
 // InitFunc init dataset
@@ -64,7 +65,12 @@ func InitFunc(pipe *pipe.Pipe) error {
 
 // Generater yacht template
 func Generater(o *project.Operator) error {
-	res, err := Convert(o.Project.Apps)
+	apps, err := o.SelectApps()
+	if err != nil {
+		return err
+	}
+
+	res, err := Convert(apps)
 	if err != nil {
 		return err
 	}
@@ -110,7 +116,7 @@ func ConvertApplication(a *project.Application) (*Template, error) {
 	t.Description = a.Overview
 	t.Categories = a.Category
 	t.Platform = "linux"
-	t.Note = a.Description
+	t.Note = a.GetNote()
 	t.Logo = a.Icon
 
 	t.Name = service.ContainerName
@@ -125,7 +131,18 @@ func ConvertApplication(a *project.Application) (*Template, error) {
 		for _, port := range service.Ports {
 			published := strconv.Itoa(int(port.Published))
 			target := strconv.Itoa(int(port.Target))
-			ports[target] = published + ":" + target + "/" + port.Protocol
+			label := target
+			if p := a.GetParameter(project.ParameterTypePort, target); p != nil {
+				label = p.GetLabel()
+			}
+			// labels must be unique keys, fallback to the target port
+			if _, ok := ports[label]; ok {
+				label = target
+			}
+			if _, ok := ports[label]; ok {
+				label = target + "/" + port.Protocol
+			}
+			ports[label] = published + ":" + target + "/" + port.Protocol
 		}
 
 		t.Ports = []map[string]string{ports}
@@ -150,11 +167,19 @@ func ConvertApplication(a *project.Application) (*Template, error) {
 			if point != nil {
 				value = *point
 			}
-			t.Environment = append(t.Environment, EnvironmentConfig{
+			env := EnvironmentConfig{
 				Name:    name,
 				Label:   name,
 				Default: value,
-			})
+			}
+
+			if p := a.GetParameter(project.ParameterTypeVariable, name); p != nil {
+				env.Label = p.GetLabel()
+				env.Description = p.Description
+				// yacht templates can not mask values
+			}
+
+			t.Environment = append(t.Environment, env)
 		}
 	}
 
@@ -0,0 +1,68 @@
+package yacht
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+func TestConvertApplicationPortLabels(t *testing.T) {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:  "test",
+		Image: "foo/test",
+		Ports: []types.ServicePortConfig{
+			{Target: 80, Published: 8080, Protocol: "tcp"},
+			{Target: 443, Published: 8443, Protocol: "tcp"},
+			{Target: 443, Published: 8443, Protocol: "udp"},
+		},
+	})
+	a.Parameters = append(a.Parameters,
+		&project.Parameter{Type: project.ParameterTypePort, Target: "80", Name: "Port"},
+		&project.Parameter{Type: project.ParameterTypePort, Target: "443", Name: "Port"},
+	)
+
+	tpl, err := ConvertApplication(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ports := tpl.Ports[0]
+	want := map[string]string{
+		"Port":    "8080:80/tcp",
+		"443":     "8443:443/tcp",
+		"443/udp": "8443:443/udp",
+	}
+	if len(ports) != len(want) {
+		t.Fatalf("ports = %v, want %v", ports, want)
+	}
+	for label, value := range want {
+		if ports[label] != value {
+			t.Errorf("ports[%s] = %q, want %q", label, ports[label], value)
+		}
+	}
+}
+
+func TestConvertApplicationMaskedLabel(t *testing.T) {
+	password := "changeme"
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, &types.ServiceConfig{
+		Name:        "test",
+		Image:       "foo/test",
+		Environment: types.MappingWithEquals{"PASSWORD": &password},
+	})
+	a.Parameters = append(a.Parameters, &project.Parameter{
+		Type: project.ParameterTypeVariable, Target: "PASSWORD", Name: "Password", Mask: true,
+	})
+
+	tpl, err := ConvertApplication(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tpl.Environment) != 1 || tpl.Environment[0].Label != "Password" {
+		t.Errorf("environment = %+v, want the plain label", tpl.Environment)
+	}
+}
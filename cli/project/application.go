@@ -1,20 +1,85 @@
 package project
 
-import "github.com/docker/cli/cli/compose/types"
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/compose/types"
+)
 
 // Application is a selfhosted application
 type Application struct {
+	ID          string
 	Name        string
 	Description string
 	Overview    string
 	Category    []string
 	Icon        string
+	IconFile    string
 	Services    []*types.ServiceConfig
+	Parameters  []*Parameter
+	WebUI       *WebUI
+	Links       []*Link
+	Deploy      *Deploy
+	Source      *Source
+
+	// popularity and freshness
+	Downloads  int64
+	Stars      int64
+	FirstSeen  time.Time
+	LastUpdate time.Time
+	Changes    string
+
+	// Variants are alternative templates of the same application
+	Variants []*Application
+}
+
+// Source of application, the loader and the location in it
+type Source struct {
+	Loader   string
+	Type     string
+	Location string
 }
 
 // NewApplication create new application
 func NewApplication() *Application {
 	return &Application{
-		Services: []*types.ServiceConfig{},
+		Services:   []*types.ServiceConfig{},
+		Parameters: []*Parameter{},
+		Links:      []*Link{},
 	}
 }
+
+// DataPathPrefix is the placeholder of app data root in volume source,
+// like !data/yarr
+const DataPathPrefix = "!data"
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify name for file names and identifiers
+func Slugify(name string) string {
+	return strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// GetID of application, fallback to the slug of name
+func (a *Application) GetID() string {
+	if a.ID != "" {
+		return a.ID
+	}
+
+	if id := Slugify(a.Name); id != "" {
+		return id
+	}
+
+	return "app"
+}
+
+// ResolveDataPath replace the data path prefix of volume source with root
+func ResolveDataPath(source, root string) string {
+	if source == DataPathPrefix || strings.HasPrefix(source, DataPathPrefix+"/") {
+		return strings.TrimSuffix(root, "/") + strings.TrimPrefix(source, DataPathPrefix)
+	}
+
+	return source
+}
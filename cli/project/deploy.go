@@ -0,0 +1,14 @@
+package project
+
+// Deploy is the placement and resources of application in orchestrators
+type Deploy struct {
+	Constraints  []string
+	Limits       Resources
+	Reservations Resources
+}
+
+// Resources of cpus and memory, like 0.5 and 512M
+type Resources struct {
+	CPUs   string
+	Memory string
+}
@@ -0,0 +1,77 @@
+package project
+
+import "strings"
+
+// Default image reference values of docker hub
+const (
+	DefaultRegistry  = "docker.io"
+	DefaultNamespace = "library"
+	DefaultTag       = "latest"
+)
+
+// Image reference like registry/repository:tag@digest
+type Image struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseImage parse and normalize image reference
+func ParseImage(ref string) *Image {
+	i := &Image{}
+	ref = strings.TrimSpace(ref)
+
+	if idx := strings.Index(ref, "@"); idx >= 0 {
+		i.Digest = ref[idx+1:]
+		ref = ref[:idx]
+	}
+
+	// tag is after the last colon which is not a part of registry host
+	if idx := strings.LastIndex(ref, ":"); idx >= 0 && !strings.Contains(ref[idx+1:], "/") {
+		i.Tag = ref[idx+1:]
+		ref = ref[:idx]
+	}
+
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		i.Registry = strings.ToLower(parts[0])
+		i.Repository = parts[1]
+	} else {
+		i.Registry = DefaultRegistry
+		i.Repository = ref
+	}
+
+	if i.Registry == "index.docker.io" || i.Registry == "registry-1.docker.io" {
+		i.Registry = DefaultRegistry
+	}
+
+	i.Repository = strings.ToLower(i.Repository)
+	if i.Registry == DefaultRegistry && !strings.Contains(i.Repository, "/") {
+		i.Repository = DefaultNamespace + "/" + i.Repository
+	}
+
+	if i.Tag == "" && i.Digest == "" {
+		i.Tag = DefaultTag
+	}
+
+	return i
+}
+
+// Name of image without tag and digest
+func (i *Image) Name() string {
+	return i.Registry + "/" + i.Repository
+}
+
+// String of the full normalized image reference
+func (i *Image) String() string {
+	s := i.Name()
+	if i.Tag != "" {
+		s += ":" + i.Tag
+	}
+	if i.Digest != "" {
+		s += "@" + i.Digest
+	}
+
+	return s
+}
@@ -0,0 +1,29 @@
+package project
+
+import "testing"
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want Image
+		str  string
+	}{
+		{"nginx", Image{"docker.io", "library/nginx", "latest", ""}, "docker.io/library/nginx:latest"},
+		{"Foo/Bar:1.0", Image{"docker.io", "foo/bar", "1.0", ""}, "docker.io/foo/bar:1.0"},
+		{"index.docker.io/library/postgres:13", Image{"docker.io", "library/postgres", "13", ""}, "docker.io/library/postgres:13"},
+		{"ghcr.io/foo/bar", Image{"ghcr.io", "foo/bar", "latest", ""}, "ghcr.io/foo/bar:latest"},
+		{"localhost:5000/bar:dev", Image{"localhost:5000", "bar", "dev", ""}, "localhost:5000/bar:dev"},
+		{"localhost/bar", Image{"localhost", "bar", "latest", ""}, "localhost/bar:latest"},
+		{"foo/bar@sha256:abc", Image{"docker.io", "foo/bar", "", "sha256:abc"}, "docker.io/foo/bar@sha256:abc"},
+		{" quay.io/foo/bar:1.0@sha256:abc ", Image{"quay.io", "foo/bar", "1.0", "sha256:abc"}, "quay.io/foo/bar:1.0@sha256:abc"},
+	}
+	for _, tt := range tests {
+		got := ParseImage(tt.ref)
+		if *got != tt.want {
+			t.Errorf("ParseImage(%q) = %+v, want %+v", tt.ref, *got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("ParseImage(%q).String() = %s, want %s", tt.ref, got.String(), tt.str)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package project
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Link types
+const (
+	LinkTypeSupport  = "support"
+	LinkTypeProject  = "project"
+	LinkTypeDonate   = "donate"
+	LinkTypeRegistry = "registry"
+)
+
+// Link of application
+type Link struct {
+	Type string
+	URL  string
+}
+
+// WebUI of application, Port is the container port
+type WebUI struct {
+	Scheme string
+	Port   uint32
+	Path   string
+}
+
+var unraidWebUIPattern = regexp.MustCompile(`^(\w+)://\[IP\](?::(?:\[PORT:(\d+)\]|(\d+)))?(.*)$`)
+
+// ParseWebUI from unraid format like http://[IP]:[PORT:8080]/path,
+// a literal port like http://[IP]:8080/path is accepted too
+func ParseWebUI(s string) *WebUI {
+	m := unraidWebUIPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil
+	}
+
+	w := &WebUI{
+		Scheme: strings.ToLower(m[1]),
+		Path:   m[4],
+	}
+
+	if port := m[2] + m[3]; port != "" {
+		p, _ := strconv.ParseUint(port, 10, 32)
+		w.Port = uint32(p)
+	} else if w.Scheme == "https" {
+		w.Port = 443
+	} else {
+		w.Port = 80
+	}
+
+	return w
+}
+
+// GetURL of web ui with host and the published port
+func (w *WebUI) GetURL(host string, port uint32) string {
+	scheme := w.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	path := w.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return scheme + "://" + host + ":" + strconv.Itoa(int(port)) + path
+}
+
+// GetWebUIPort published on host, 0 when no web ui
+func (a *Application) GetWebUIPort() uint32 {
+	if a.WebUI == nil {
+		return 0
+	}
+
+	for _, service := range a.Services {
+		for _, port := range service.Ports {
+			if port.Target == a.WebUI.Port && port.Published != 0 {
+				return port.Published
+			}
+		}
+	}
+
+	return a.WebUI.Port
+}
+
+// GetWebUIURL with host, empty when no web ui
+func (a *Application) GetWebUIURL(host string) string {
+	if a.WebUI == nil {
+		return ""
+	}
+
+	return a.WebUI.GetURL(host, a.GetWebUIPort())
+}
+
+// GetLink of application by type
+func (a *Application) GetLink(t string) *Link {
+	for _, l := range a.Links {
+		if l.Type == t {
+			return l
+		}
+	}
+
+	return nil
+}
+
+// GetNote of application for templates, in html
+func (a *Application) GetNote() string {
+	lines := []string{}
+	if a.Description != "" {
+		lines = append(lines, html.EscapeString(a.Description))
+	}
+
+	if url := a.GetWebUIURL("IP"); url != "" {
+		lines = append(lines, "Web UI: "+html.EscapeString(url))
+	}
+
+	for _, l := range a.Links {
+		if l.Type == "" || l.URL == "" {
+			continue
+		}
+		url := html.EscapeString(l.URL)
+		title := strings.ToUpper(l.Type[:1]) + l.Type[1:]
+		lines = append(lines, title+": <a href=\""+url+"\" target=\"_blank\">"+url+"</a>")
+	}
+
+	return strings.Join(lines, "<br/>")
+}
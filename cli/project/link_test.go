@@ -0,0 +1,67 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+)
+
+func TestParseWebUI(t *testing.T) {
+	tests := []struct {
+		s    string
+		want *WebUI
+	}{
+		{"http://[IP]:[PORT:8080]/", &WebUI{Scheme: "http", Port: 8080, Path: "/"}},
+		{"https://[IP]:[PORT:8443]/admin", &WebUI{Scheme: "https", Port: 8443, Path: "/admin"}},
+		{"http://[IP]:8080/", &WebUI{Scheme: "http", Port: 8080, Path: "/"}},
+		{" HTTP://[IP]:9000 ", &WebUI{Scheme: "http", Port: 9000, Path: ""}},
+		{"http://[IP]/web", &WebUI{Scheme: "http", Port: 80, Path: "/web"}},
+		{"https://[IP]", &WebUI{Scheme: "https", Port: 443, Path: ""}},
+		{"http://192.168.1.10:8080/", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := ParseWebUI(tt.s)
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("ParseWebUI(%q) = %+v, want %+v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGetWebUIPort(t *testing.T) {
+	tests := []struct {
+		webui *WebUI
+		ports []types.ServicePortConfig
+		want  uint32
+		url   string
+	}{
+		{nil, nil, 0, ""},
+		{ParseWebUI("http://[IP]:8080/"), []types.ServicePortConfig{{Target: 8080, Published: 18080}}, 18080, "http://IP:18080/"},
+		{ParseWebUI("http://[IP]:[PORT:8080]"), []types.ServicePortConfig{{Target: 8080}}, 8080, "http://IP:8080/"},
+		{ParseWebUI("https://[IP]:[PORT:443]/ui"), nil, 443, "https://IP:443/ui"},
+	}
+	for _, tt := range tests {
+		a := NewApplication()
+		a.WebUI = tt.webui
+		a.Services = append(a.Services, &types.ServiceConfig{Name: "app", Ports: tt.ports})
+
+		if got := a.GetWebUIPort(); got != tt.want {
+			t.Errorf("GetWebUIPort() of %+v = %d, want %d", tt.webui, got, tt.want)
+		}
+		if got := a.GetWebUIURL("IP"); got != tt.url {
+			t.Errorf("GetWebUIURL() of %+v = %q, want %q", tt.webui, got, tt.url)
+		}
+	}
+}
+
+func TestGetNoteEscapes(t *testing.T) {
+	a := NewApplication()
+	a.Description = `<script>alert("x")</script> & more`
+	a.Links = append(a.Links, &Link{Type: LinkTypeProject, URL: `https://example.com/?a=1&b="2"`})
+
+	want := `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; more<br/>` +
+		`Project: <a href="https://example.com/?a=1&amp;b=&#34;2&#34;" target="_blank">https://example.com/?a=1&amp;b=&#34;2&#34;</a>`
+	if got := a.GetNote(); got != want {
+		t.Errorf("GetNote() = %s, want %s", got, want)
+	}
+}
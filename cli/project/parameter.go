@@ -0,0 +1,62 @@
+package project
+
+// Parameter types
+const (
+	ParameterTypePort     = "Port"
+	ParameterTypePath     = "Path"
+	ParameterTypeVariable = "Variable"
+	ParameterTypeDevice   = "Device"
+	ParameterTypeLabel    = "Label"
+	ParameterTypeSecret   = "Secret"
+)
+
+// Parameter display modes
+const (
+	DisplayAlways   = "always"
+	DisplayAdvanced = "advanced"
+	DisplayHidden   = "hidden"
+)
+
+// Parameter is the metadata of a configurable item in template,
+// Target is the container port, container path or environment name,
+// secrets target the environment name too.
+type Parameter struct {
+	Type        string
+	Target      string
+	Name        string
+	Description string
+	Default     string
+	Display     string
+	Required    bool
+	Mask        bool
+}
+
+// GetLabel of parameter, fallback to target
+func (p *Parameter) GetLabel() string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	return p.Target
+}
+
+// IsAdvanced parameter should not be shown by default
+func (p *Parameter) IsAdvanced() bool {
+	return p.Display == DisplayAdvanced || p.Display == DisplayHidden
+}
+
+// IsSecret parameter, secret parameters or masked variables
+func (p *Parameter) IsSecret() bool {
+	return p.Type == ParameterTypeSecret || (p.Type == ParameterTypeVariable && p.Mask)
+}
+
+// GetParameter of the application by type and target
+func (a *Application) GetParameter(t, target string) *Parameter {
+	for _, p := range a.Parameters {
+		if p.Type == t && p.Target == target {
+			return p
+		}
+	}
+
+	return nil
+}
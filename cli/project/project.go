@@ -65,10 +65,19 @@ func NewProject(cfg *viper.Viper) *Project {
 // Run the project
 func (p *Project) Run() error {
 	for _, o := range p.Loaders {
+		loaded := len(p.Apps)
 		err := loaders[o.Type](o)
 		if err != nil {
 			return fmt.Errorf("run loader %s(%s) error: %s", o.Name, o.Type, err)
 		}
+
+		for _, a := range p.Apps[loaded:] {
+			if a.Source == nil {
+				a.Source = &Source{}
+			}
+			a.Source.Loader = o.Name
+			a.Source.Type = o.Type
+		}
 	}
 
 	for _, o := range p.Generaters {
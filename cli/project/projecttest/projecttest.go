@@ -0,0 +1,31 @@
+package projecttest
+
+import (
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/yankghjh/selfhosted_store/cli/project"
+)
+
+// NewApp named Test with services, the fixture of generater tests
+func NewApp(services ...*types.ServiceConfig) *project.Application {
+	a := project.NewApplication()
+	a.Name = "Test"
+	a.Services = append(a.Services, services...)
+
+	return a
+}
+
+// NewService of image with environment values
+func NewService(name, image string, env map[string]string) *types.ServiceConfig {
+	return &types.ServiceConfig{Name: name, Image: image, Environment: Env(env)}
+}
+
+// Env of compose service from values
+func Env(values map[string]string) types.MappingWithEquals {
+	env := types.MappingWithEquals{}
+	for k, v := range values {
+		v := v
+		env[k] = &v
+	}
+
+	return env
+}
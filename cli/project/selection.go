@@ -0,0 +1,127 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sort keys of apps
+const (
+	SortByName      = "name"
+	SortByDownloads = "downloads"
+	SortByStars     = "stars"
+	SortByUpdated   = "updated"
+	SortByFirstSeen = "first_seen"
+)
+
+// SelectApps of project by the selection config of generater:
+//
+//	sort: downloads
+//	top_per_category: 20
+//	mark:
+//	  new_days: 30
+//	  new_category: New
+//	  updated_days: 14
+//	  updated_category: Updated
+func (o *Operator) SelectApps() ([]*Application, error) {
+	apps := make([]*Application, len(o.Project.Apps))
+	copy(apps, o.Project.Apps)
+
+	if key := o.Config.GetString("sort"); key != "" {
+		if err := SortApps(apps, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if n := o.Config.GetInt("top_per_category"); n > 0 {
+		apps = TopPerCategory(apps, n)
+	}
+
+	o.Config.SetDefault("mark.new_category", "New")
+	o.Config.SetDefault("mark.updated_category", "Updated")
+	newDays := o.Config.GetInt("mark.new_days")
+	updatedDays := o.Config.GetInt("mark.updated_days")
+	if newDays > 0 || updatedDays > 0 {
+		now := time.Now()
+		for i, a := range apps {
+			mark := ""
+			if newDays > 0 && a.IsNew(now, newDays) {
+				mark = o.Config.GetString("mark.new_category")
+			} else if updatedDays > 0 && a.IsUpdated(now, updatedDays) {
+				mark = o.Config.GetString("mark.updated_category")
+			}
+
+			if mark != "" {
+				// copy the app, it is shared by other generaters
+				marked := *a
+				marked.Category = append(append([]string{}, a.Category...), mark)
+				apps[i] = &marked
+			}
+		}
+	}
+
+	return apps, nil
+}
+
+// SortApps by key, the most popular or the freshest comes first
+func SortApps(apps []*Application, key string) error {
+	var less func(a, b *Application) bool
+	switch key {
+	case SortByName:
+		less = func(a, b *Application) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case SortByDownloads:
+		less = func(a, b *Application) bool { return a.Downloads > b.Downloads }
+	case SortByStars:
+		less = func(a, b *Application) bool { return a.Stars > b.Stars }
+	case SortByUpdated:
+		less = func(a, b *Application) bool { return a.LastUpdate.After(b.LastUpdate) }
+	case SortByFirstSeen:
+		less = func(a, b *Application) bool { return a.FirstSeen.After(b.FirstSeen) }
+	default:
+		return fmt.Errorf("unknown sort key [%s]", key)
+	}
+
+	sort.SliceStable(apps, func(i, j int) bool { return less(apps[i], apps[j]) })
+
+	return nil
+}
+
+// TopPerCategory keep the first n apps of each category,
+// app is kept when it is in the top n of any of its categories
+func TopPerCategory(apps []*Application, n int) []*Application {
+	counts := map[string]int{}
+	result := []*Application{}
+
+	for _, a := range apps {
+		categories := a.Category
+		if len(categories) == 0 {
+			categories = []string{""}
+		}
+
+		keep := false
+		for _, c := range categories {
+			if counts[c] < n {
+				keep = true
+			}
+			counts[c]++
+		}
+
+		if keep {
+			result = append(result, a)
+		}
+	}
+
+	return result
+}
+
+// IsNew when the app is first seen in the last days
+func (a *Application) IsNew(now time.Time, days int) bool {
+	return !a.FirstSeen.IsZero() && now.Sub(a.FirstSeen) <= time.Duration(days)*24*time.Hour
+}
+
+// IsUpdated when the app is updated in the last days
+func (a *Application) IsUpdated(now time.Time, days int) bool {
+	return !a.LastUpdate.IsZero() && now.Sub(a.LastUpdate) <= time.Duration(days)*24*time.Hour
+}
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestSelectApps(t *testing.T) {
+	now := time.Now()
+	p := NewProject(viper.New())
+	for _, a := range []struct {
+		name       string
+		category   string
+		downloads  int64
+		firstSeen  time.Time
+		lastUpdate time.Time
+	}{
+		{"Plex", "Media", 10, now.AddDate(-1, 0, 0), now.AddDate(0, 0, -3)},
+		{"Jellyfin", "Media", 30, now.AddDate(0, 0, -5), now.AddDate(0, 0, -5)},
+		{"Nextcloud", "Cloud", 20, now.AddDate(-1, 0, 0), now.AddDate(-1, 0, 0)},
+		{"Syncthing", "Cloud", 5, time.Time{}, time.Time{}},
+	} {
+		app := NewApplication()
+		app.Name = a.name
+		app.Category = []string{a.category}
+		app.Downloads = a.downloads
+		app.FirstSeen = a.firstSeen
+		app.LastUpdate = a.lastUpdate
+		p.Apps = append(p.Apps, app)
+	}
+
+	tests := []struct {
+		config map[string]interface{}
+		want   []string
+	}{
+		{map[string]interface{}{}, []string{"Plex:Media", "Jellyfin:Media", "Nextcloud:Cloud", "Syncthing:Cloud"}},
+		{map[string]interface{}{"sort": "name"}, []string{"Jellyfin:Media", "Nextcloud:Cloud", "Plex:Media", "Syncthing:Cloud"}},
+		{map[string]interface{}{"sort": "downloads", "top_per_category": 1}, []string{"Jellyfin:Media", "Nextcloud:Cloud"}},
+		{
+			map[string]interface{}{"sort": "downloads", "mark": map[string]interface{}{"new_days": 30, "updated_days": 7}},
+			[]string{"Jellyfin:Media,New", "Nextcloud:Cloud", "Plex:Media,Updated", "Syncthing:Cloud"},
+		},
+	}
+	for _, tt := range tests {
+		cfg := viper.New()
+		for k, v := range tt.config {
+			cfg.Set(k, v)
+		}
+
+		apps, err := (&Operator{Config: cfg, Project: p}).SelectApps()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, a := range apps {
+			got = append(got, a.Name+":"+strings.Join(a.Category, ","))
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("SelectApps(%v) = %v, want %v", tt.config, got, tt.want)
+		}
+	}
+
+	for _, a := range p.Apps {
+		if len(a.Category) != 1 {
+			t.Errorf("project app %s is modified: %v", a.Name, a.Category)
+		}
+	}
+
+	cfg := viper.New()
+	cfg.Set("sort", "size")
+	if _, err := (&Operator{Config: cfg, Project: p}).SelectApps(); err == nil {
+		t.Errorf("SelectApps with unknown sort key returns no error")
+	}
+}